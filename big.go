@@ -0,0 +1,130 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// BigVersion is a version whose major, minor and patch numbers may exceed
+// uint64, like the timestamps some calendar versioning schemes use as
+// numbers. Prerelease versions are the same as for Version.
+type BigVersion struct {
+	Major *big.Int
+	Minor *big.Int
+	Patch *big.Int
+	Pre   []PRVersion
+	Build []string //No Precedence
+}
+
+// ParseBig parses a version like Parse, but without limiting the size of the
+// major, minor and patch numbers.
+func ParseBig(s string) (BigVersion, error) {
+	if len(s) == 0 {
+		return BigVersion{}, errors.New("Version string empty")
+	}
+
+	// strip off any leading 'v' if present
+	s = strings.TrimPrefix(s, "v")
+
+	// Split into major.minor.(patch+pr+meta)
+	parts := strings.SplitN(s, ".", 3)
+	if len(parts) != 3 {
+		return BigVersion{}, errors.New("No Major.Minor.Patch elements found")
+	}
+
+	// Patch ends at the first prerelease or build meta data separator
+	tail := ""
+	if i := strings.IndexAny(parts[2], "-+"); i != -1 {
+		parts[2], tail = parts[2][:i], parts[2][i:]
+	}
+
+	var nums [3]*big.Int
+	for i, name := range []string{"Major", "Minor", "Patch"} {
+		p := parts[i]
+		if !containsOnly(p, numbers) {
+			return BigVersion{}, fmt.Errorf("Invalid character(s) found in %s number %q", strings.ToLower(name), p)
+		}
+		if hasLeadingZeroes(p) {
+			return BigVersion{}, fmt.Errorf("%s number must not contain leading zeroes %q", name, p)
+		}
+		n, ok := new(big.Int).SetString(p, 10)
+		if !ok {
+			return BigVersion{}, fmt.Errorf("Invalid %s number %q", strings.ToLower(name), p)
+		}
+		nums[i] = n
+	}
+
+	var rest Version
+	if err := parsePreBuild(&rest, tail); err != nil {
+		return BigVersion{}, err
+	}
+	return BigVersion{Major: nums[0], Minor: nums[1], Patch: nums[2], Pre: rest.Pre, Build: rest.Build}, nil
+}
+
+// Big converts v into a BigVersion.
+func (v Version) Big() BigVersion {
+	c := v.Clone()
+	return BigVersion{
+		Major: new(big.Int).SetUint64(v.Major),
+		Minor: new(big.Int).SetUint64(v.Minor),
+		Patch: new(big.Int).SetUint64(v.Patch),
+		Pre:   c.Pre,
+		Build: c.Build,
+	}
+}
+
+// Version converts v into a Version. It returns false if the major, minor
+// or patch number does not fit into an uint64.
+func (v BigVersion) Version() (Version, bool) {
+	for _, n := range []*big.Int{v.Major, v.Minor, v.Patch} {
+		if n == nil || !n.IsUint64() {
+			return Version{}, false
+		}
+	}
+	c := Version{Pre: v.Pre, Build: v.Build}.Clone()
+	c.Major, c.Minor, c.Patch = v.Major.Uint64(), v.Minor.Uint64(), v.Patch.Uint64()
+	return c, true
+}
+
+// Version to string
+func (v BigVersion) String() string {
+	var b strings.Builder
+	for i, n := range []*big.Int{v.Major, v.Minor, v.Patch} {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		if n == nil {
+			b.WriteByte('0')
+		} else {
+			b.WriteString(n.String())
+		}
+	}
+	// the prerelease and build meta data are formatted like for Version
+	rest := Version{Pre: v.Pre, Build: v.Build}.String()
+	b.WriteString(strings.TrimPrefix(rest, "0.0.0"))
+	return b.String()
+}
+
+// Compare compares BigVersions v to o like Version.Compare:
+// -1 == v is less than o
+// 0 == v is equal to o
+// 1 == v is greater than o
+func (v BigVersion) Compare(o BigVersion) int {
+	for i, n := range []*big.Int{v.Major, v.Minor, v.Patch} {
+		m := []*big.Int{o.Major, o.Minor, o.Patch}[i]
+		if c := bigOrZero(n).Cmp(bigOrZero(m)); c != 0 {
+			return c
+		}
+	}
+	return ComparePrerelease(v.Pre, o.Pre)
+}
+
+// bigOrZero returns n, or zero if n is nil.
+func bigOrZero(n *big.Int) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParseBig(t *testing.T) {
+	tests := []struct {
+		s string
+		o string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v1.2.3-rc.1+build.5", "1.2.3-rc.1+build.5"},
+		{"20230101120000123456789.1.2", "20230101120000123456789.1.2"},
+		{"1.18446744073709551616.18446744073709551617-alpha", "1.18446744073709551616.18446744073709551617-alpha"},
+		// errors
+		{"", ""},
+		{"1.2", ""},
+		{"01.2.3", ""},
+		{"1.x.3", ""},
+		{"1.2.3-01", ""},
+		{"1.2.3+", ""},
+	}
+	for _, test := range tests {
+		v, err := ParseBig(test.s)
+		if test.o == "" {
+			if err == nil {
+				t.Errorf("Parsing big %q, expected error but got %q", test.s, v)
+			}
+		} else if err != nil {
+			t.Errorf("Parsing big %q, unexpected error %q", test.s, err)
+		} else if v.String() != test.o {
+			t.Errorf("Parsing big %q, expected %q but got %q", test.s, test.o, v)
+		}
+	}
+}
+
+func TestBigVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		c    int
+	}{
+		{"18446744073709551616.0.0", "18446744073709551615.0.0", 1},
+		{"18446744073709551616.0.0", "18446744073709551616.0.0", 0},
+		{"1.18446744073709551616.0", "1.18446744073709551617.0", -1},
+		{"1.2.18446744073709551616-rc.1", "1.2.18446744073709551616", -1},
+		{"1.2.3-rc.2", "1.2.3-rc.10", -1},
+		{"1.2.3+a", "1.2.3+b", 0},
+	}
+	for _, test := range tests {
+		a, err := ParseBig(test.a)
+		if err != nil {
+			t.Fatalf("Parsing big %q, unexpected error %q", test.a, err)
+		}
+		b, err := ParseBig(test.b)
+		if err != nil {
+			t.Fatalf("Parsing big %q, unexpected error %q", test.b, err)
+		}
+		if c := a.Compare(b); c != test.c {
+			t.Errorf("Comparing %q and %q, expected %d but got %d", test.a, test.b, test.c, c)
+		}
+		if c := b.Compare(a); c != -test.c {
+			t.Errorf("Comparing %q and %q, expected %d but got %d", test.b, test.a, -test.c, c)
+		}
+	}
+
+	// small versions compare like Version
+	for _, a := range formatTests {
+		for _, b := range formatTests {
+			if c, bc := a.v.Compare(b.v), a.v.Big().Compare(b.v.Big()); c != bc {
+				t.Errorf("Comparing %q and %q, expected %d but got %d", a.v, b.v, c, bc)
+			}
+		}
+	}
+}
+
+func TestBigVersionConversion(t *testing.T) {
+	v := MustParse("1.2.3-rc.1+build")
+	b := v.Big()
+	if b.String() != v.String() {
+		t.Errorf("Expected %q, got %q", v, b)
+	}
+	if o, ok := b.Version(); !ok || o.String() != v.String() {
+		t.Errorf("Expected %q, got %q, %t", v, o, ok)
+	}
+	b.Pre[0] = prstr("beta")
+	if v.String() != "1.2.3-rc.1+build" {
+		t.Errorf("Expected original to be unchanged, got %q", v)
+	}
+
+	huge, _ := ParseBig("18446744073709551616.0.0")
+	if o, ok := huge.Version(); ok {
+		t.Errorf("Expected %q not to fit a Version, got %q", huge, o)
+	}
+}
@@ -0,0 +1,116 @@
+package semver
+
+import (
+	"fmt"
+)
+
+// DiffType is the most significant component in which two versions differ,
+// which is also the kind of release leading from one to the other.
+type DiffType int
+
+// Types of differences between versions, from least to most significant.
+const (
+	DiffNone DiffType = iota
+	DiffPre
+	DiffPatch
+	DiffMinor
+	DiffMajor
+)
+
+// DiffType to string
+func (d DiffType) String() string {
+	switch d {
+	case DiffNone:
+		return "none"
+	case DiffPre:
+		return "prerelease"
+	case DiffPatch:
+		return "patch"
+	case DiffMinor:
+		return "minor"
+	case DiffMajor:
+		return "major"
+	}
+	return fmt.Sprintf("DiffType(%d)", int(d))
+}
+
+// Diff returns the most significant component in which v and o differ.
+// Build meta data is ignored.
+func (v Version) Diff(o Version) DiffType {
+	switch {
+	case v.Major != o.Major:
+		return DiffMajor
+	case v.Minor != o.Minor:
+		return DiffMinor
+	case v.Patch != o.Patch:
+		return DiffPatch
+	case v.Compare(o) != 0:
+		return DiffPre
+	}
+	return DiffNone
+}
+
+// Bump returns a copy of v with the given release type applied.
+// DiffMajor, DiffMinor and DiffPatch increment the respective component,
+// zero the lower ones and remove prerelease and build meta data. Unlike
+// IncrementMajor, IncrementMinor and IncrementPatch they also apply to major
+// version 0, so 0.2.3 bumps to 0.2.4, 0.3.0 or 1.0.0. DiffPre increments the
+// prerelease like IncrementPrerelease. DiffNone returns v unchanged.
+func (v Version) Bump(d DiffType) (Version, error) {
+	switch d {
+	case DiffNone:
+		return v.Clone(), nil
+	case DiffPre:
+		b := v.Clone()
+		if err := b.IncrementPrerelease(); err != nil {
+			return Version{}, err
+		}
+		return b, nil
+	case DiffPatch:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}, nil
+	case DiffMinor:
+		return Version{Major: v.Major, Minor: v.Minor + 1}, nil
+	case DiffMajor:
+		return Version{Major: v.Major + 1}, nil
+	}
+	return Version{}, fmt.Errorf("Invalid release type %s for %q", d, v.String())
+}
+
+// PrereleaseDiff compares the prerelease identifiers of a and b position by
+// position. Where they differ, the identifier of a is reported as removed
+// and the one of b as added, so 1.0.0-rc.1 and 1.0.0-rc.2 differ by the
+// removed "1" and the added "2".
+func PrereleaseDiff(a, b Version) (added, removed []string) {
+	for i := 0; i < len(a.Pre) || i < len(b.Pre); i++ {
+		if i < len(a.Pre) && i < len(b.Pre) && a.Pre[i].Compare(b.Pre[i]) == 0 {
+			continue
+		}
+		if i < len(a.Pre) {
+			removed = append(removed, a.Pre[i].String())
+		}
+		if i < len(b.Pre) {
+			added = append(added, b.Pre[i].String())
+		}
+	}
+	return added, removed
+}
+
+// ClassifyUpgrades groups the candidates greater than current by their
+// Diff to current, so every upgrade lands in the bucket of the most
+// significant component it changes. Upgrades from a prerelease to another
+// prerelease or the release of the same core are classified as DiffPre.
+// The versions of each bucket are sorted in ascending order, and buckets
+// without upgrades are left out.
+func ClassifyUpgrades(current Version, candidates []Version) map[DiffType][]Version {
+	upgrades := make(map[DiffType][]Version)
+	for _, c := range candidates {
+		if c.GT(current) {
+			d := current.Diff(c)
+			upgrades[d] = append(upgrades[d], c)
+		}
+	}
+	for _, vs := range upgrades {
+		Sort(vs)
+	}
+	return upgrades
+}
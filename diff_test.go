@@ -0,0 +1,155 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		v1 string
+		v2 string
+		d  DiffType
+	}{
+		{"1.2.3", "1.2.3", DiffNone},
+		{"1.2.3", "1.2.3+build", DiffNone},
+		{"1.2.3", "1.2.3-rc.1", DiffPre},
+		{"1.2.3-rc.1", "1.2.3-rc.2", DiffPre},
+		{"1.2.3", "1.2.4", DiffPatch},
+		{"1.2.3", "1.2.4-rc.1", DiffPatch},
+		{"1.2.3", "1.3.0", DiffMinor},
+		{"1.2.3", "1.3.3", DiffMinor},
+		{"1.2.3", "2.2.3", DiffMajor},
+		{"1.2.3", "0.2.3", DiffMajor},
+	}
+	for _, test := range tests {
+		v1, v2 := MustParse(test.v1), MustParse(test.v2)
+		if d := v1.Diff(v2); d != test.d {
+			t.Errorf("Diff %q and %q, expected %s but got %s", v1, v2, test.d, d)
+		}
+		if d := v2.Diff(v1); d != test.d {
+			t.Errorf("Diff %q and %q, expected %s but got %s", v2, v1, test.d, d)
+		}
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		v        string
+		d        DiffType
+		expected string
+	}{
+		{"1.2.3", DiffMajor, "2.0.0"},
+		{"1.2.3", DiffMinor, "1.3.0"},
+		{"1.2.3", DiffPatch, "1.2.4"},
+		{"1.2.3-rc.1+build", DiffMajor, "2.0.0"},
+		{"1.2.3-rc.1+build", DiffPatch, "1.2.4"},
+		{"1.2.3-rc.1+build", DiffPre, "1.2.3-rc.2+build"},
+		{"1.2.3-rc", DiffPre, "1.2.3-rc.1"},
+		{"1.2.3-rc.1", DiffNone, "1.2.3-rc.1"},
+		{"0.2.3", DiffMajor, "1.0.0"},
+		{"0.2.3", DiffMinor, "0.3.0"},
+		{"0.2.3", DiffPatch, "0.2.4"},
+		{"0.2.3-rc.1", DiffPatch, "0.2.4"},
+		{"0.2.3-rc.1+build", DiffMinor, "0.3.0"},
+		{"0.0.0", DiffPatch, "0.0.1"},
+		// errors
+		{"1.2.3", DiffPre, ""},
+		{"1.2.3", DiffType(42), ""},
+	}
+	for _, test := range tests {
+		v := MustParse(test.v)
+		b, err := v.Bump(test.d)
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("Bump %q by %s, expecting error, got %q", v, test.d, b)
+			}
+		} else if err != nil {
+			t.Errorf("Bump %q by %s, not expecting error, got %q", v, test.d, err)
+		} else if b.String() != test.expected {
+			t.Errorf("Bump %q by %s, expecting %q, got %q", v, test.d, test.expected, b)
+		}
+		if v.String() != test.v {
+			t.Errorf("Bump %q by %s modified the version to %q", test.v, test.d, v)
+		}
+	}
+}
+
+func TestDiffTypeString(t *testing.T) {
+	for d, s := range map[DiffType]string{
+		DiffNone: "none", DiffPre: "prerelease", DiffPatch: "patch", DiffMinor: "minor", DiffMajor: "major",
+		DiffType(42): "DiffType(42)",
+	} {
+		if d.String() != s {
+			t.Errorf("Expected %q, got %q", s, d.String())
+		}
+	}
+}
+
+func TestPrereleaseDiff(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		added   []string
+		removed []string
+	}{
+		{"1.0.0-rc.1", "1.0.0-rc.2", []string{"2"}, []string{"1"}},
+		{"1.0.0-alpha", "1.0.0-beta.1", []string{"beta", "1"}, []string{"alpha"}},
+		{"1.0.0-rc", "1.0.0-rc.1", []string{"1"}, nil},
+		{"1.0.0-rc.1", "1.0.0", nil, []string{"rc", "1"}},
+		{"1.0.0-rc.1", "2.0.0-rc.1+build", nil, nil},
+		{"1.0.0", "1.0.0", nil, nil},
+	}
+	for _, tc := range tests {
+		added, removed := PrereleaseDiff(MustParse(tc.a), MustParse(tc.b))
+		if !reflect.DeepEqual(added, tc.added) || !reflect.DeepEqual(removed, tc.removed) {
+			t.Errorf("Invalid for case %q, %q: Expected %q, %q, got: %q, %q", tc.a, tc.b, tc.added, tc.removed, added, removed)
+		}
+	}
+}
+
+func TestClassifyUpgrades(t *testing.T) {
+	candidates := []Version{
+		MustParse("2.0.0"),
+		MustParse("1.2.4"),
+		MustParse("1.3.0-rc.1"),
+		MustParse("1.2.3"),
+		MustParse("1.2.2"),
+		MustParse("0.9.0"),
+		MustParse("1.2.10"),
+		MustParse("1.3.0"),
+		MustParse("1.2.3-rc.2"),
+		MustParse("3.1.0+build"),
+	}
+	tests := []struct {
+		current string
+		o       map[DiffType][]string
+	}{
+		{"1.2.3", map[DiffType][]string{
+			DiffPatch: {"1.2.4", "1.2.10"},
+			DiffMinor: {"1.3.0-rc.1", "1.3.0"},
+			DiffMajor: {"2.0.0", "3.1.0+build"},
+		}},
+		{"1.2.3-rc.1", map[DiffType][]string{
+			DiffPre:   {"1.2.3-rc.2", "1.2.3"},
+			DiffPatch: {"1.2.4", "1.2.10"},
+			DiffMinor: {"1.3.0-rc.1", "1.3.0"},
+			DiffMajor: {"2.0.0", "3.1.0+build"},
+		}},
+		{"2.0.0", map[DiffType][]string{
+			DiffMajor: {"3.1.0+build"},
+		}},
+		{"3.1.0", map[DiffType][]string{}},
+	}
+	for _, tc := range tests {
+		upgrades := ClassifyUpgrades(MustParse(tc.current), candidates)
+		o := make(map[DiffType][]string, len(upgrades))
+		for d, vs := range upgrades {
+			for _, v := range vs {
+				o[d] = append(o[d], v.String())
+			}
+		}
+		if !reflect.DeepEqual(o, tc.o) {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.current, tc.o, o)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseErrorKind classifies a ParseError.
+type ParseErrorKind int
+
+// Kinds of ParseError.
+const (
+	// ErrNumberOverflow is the kind of error for a number too large for an
+	// uint64, see ParseBig for versions with such numbers.
+	ErrNumberOverflow ParseErrorKind = iota + 1
+	// ErrEmptyComponent is the kind of error for an empty prerelease or
+	// build identifier, e.g. in "1.2.3-", "1.2.3-rc..1" or "1.2.3+".
+	ErrEmptyComponent
+)
+
+// ParseError is returned by Parse for errors callers may want to handle
+// specifically. It carries the kind of error and the version component it
+// occurred in, which is one of "major", "minor", "patch", "prerelease" or
+// "build meta data".
+type ParseError struct {
+	Kind      ParseErrorKind
+	Component string
+	Value     string
+}
+
+func (e *ParseError) Error() string {
+	component := e.Component
+	if len(component) > 0 {
+		component = strings.ToUpper(component[:1]) + component[1:]
+	}
+	switch e.Kind {
+	case ErrNumberOverflow:
+		return fmt.Sprintf("%s number %q is too large", component, e.Value)
+	case ErrEmptyComponent:
+		return fmt.Sprintf("%s is empty", component)
+	}
+	return fmt.Sprintf("Invalid %s %q", e.Component, e.Value)
+}
+
+// parseNumber parses the decimal number s of the given version component,
+// returning a ParseError if it overflows.
+func parseNumber(s, component string) (uint64, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+		return 0, &ParseError{Kind: ErrNumberOverflow, Component: component, Value: s}
+	}
+	return n, err
+}
@@ -0,0 +1,85 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParseErrorNumberOverflow(t *testing.T) {
+	tests := []struct {
+		s         string
+		component string
+	}{
+		{"99999999999999999999999.0.0", "major"},
+		{"1.18446744073709551616.0", "minor"},
+		{"1.2.18446744073709551616", "patch"},
+		{"1.2.3-18446744073709551616", "prerelease"},
+		{"1.2.3-rc.99999999999999999999999+build", "prerelease"},
+	}
+	for _, test := range tests {
+		for _, parse := range []func(string) (Version, error){Parse, ParseTolerant, func(s string) (Version, error) {
+			return ParseBytes([]byte(s))
+		}} {
+			_, err := parse(test.s)
+			pe, ok := err.(*ParseError)
+			if !ok {
+				t.Errorf("Parsing %q, expected ParseError but got %#v", test.s, err)
+				continue
+			}
+			if pe.Kind != ErrNumberOverflow || pe.Component != test.component {
+				t.Errorf("Parsing %q, expected overflow of %s, got %#v", test.s, test.component, pe)
+			}
+		}
+	}
+
+	if _, err := Parse("18446744073709551615.0.0"); err != nil {
+		t.Errorf("Unexpected error for the maximum major number: %s", err)
+	}
+	for _, s := range []string{"1.2", "a.2.3", "1.2.3-01"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parsing %q, expected error but got none", s)
+		} else if _, ok := err.(*ParseError); ok {
+			t.Errorf("Parsing %q, expected no ParseError but got %q", s, err)
+		}
+	}
+}
+
+func TestParseErrorEmptyComponent(t *testing.T) {
+	tests := []struct {
+		s         string
+		component string
+	}{
+		{"1.2.3-", "prerelease"},
+		{"1.2.3-.", "prerelease"},
+		{"1.2.3-a..b", "prerelease"},
+		{"1.2.3-rc.", "prerelease"},
+		{"1.2.3-+build", "prerelease"},
+		{"1.2.3+", "build meta data"},
+		{"1.2.3-rc.1+build..5", "build meta data"},
+	}
+	for _, test := range tests {
+		for _, parse := range []func(string) (Version, error){Parse, func(s string) (Version, error) {
+			return ParseBytes([]byte(s))
+		}} {
+			_, err := parse(test.s)
+			pe, ok := err.(*ParseError)
+			if !ok {
+				t.Errorf("Parsing %q, expected ParseError but got %#v", test.s, err)
+				continue
+			}
+			if pe.Kind != ErrEmptyComponent || pe.Component != test.component {
+				t.Errorf("Parsing %q, expected empty %s, got %#v", test.s, test.component, pe)
+			}
+		}
+	}
+
+	if _, err := NewPRVersion(""); err == nil || err.Error() != "Prerelease is empty" {
+		t.Errorf("Expected error %q, got %v", "Prerelease is empty", err)
+	}
+}
+
+func TestParseErrorString(t *testing.T) {
+	err := &ParseError{Kind: ErrNumberOverflow, Component: "major", Value: "99999999999999999999"}
+	if s := err.Error(); s != `Major number "99999999999999999999" is too large` {
+		t.Errorf("Unexpected error string %q", s)
+	}
+}
@@ -0,0 +1,57 @@
+package semver
+
+import (
+	"fmt"
+)
+
+// Layout of the fixed size binary form: major, minor and patch take 5 bytes
+// each, big endian, followed by a byte of flags. Comparing the bytes of two
+// stable versions orders them by precedence.
+const (
+	fixedComponentSize = 5
+	maxFixedComponent  = 1<<(8*fixedComponentSize) - 1
+	fixedFlagsIndex    = 3 * fixedComponentSize
+
+	// fixedFlagPrerelease marks a version with prerelease
+	fixedFlagPrerelease = 1 << 0
+)
+
+// MarshalFixed packs v into 16 bytes for fixed size storage. Major, minor and
+// patch must each fit into 40 bits. The prerelease identifiers and the build
+// meta data are not stored, only whether v has a prerelease.
+func (v Version) MarshalFixed() ([16]byte, error) {
+	var b [16]byte
+	for i, n := range []uint64{v.Major, v.Minor, v.Patch} {
+		if n > maxFixedComponent {
+			return b, fmt.Errorf("Version number %d of %q does not fit into %d bytes", n, v.String(), fixedComponentSize)
+		}
+		for j := fixedComponentSize - 1; j >= 0; j-- {
+			b[i*fixedComponentSize+j] = byte(n)
+			n >>= 8
+		}
+	}
+	if len(v.Pre) > 0 {
+		b[fixedFlagsIndex] |= fixedFlagPrerelease
+	}
+	return b, nil
+}
+
+// UnmarshalFixed unpacks the 16 bytes of MarshalFixed into v. As the
+// prerelease identifiers are not stored, a version which had a prerelease
+// gets the prerelease "0", which is the lowest of its core.
+func (v *Version) UnmarshalFixed(b [16]byte) error {
+	if flags := b[fixedFlagsIndex]; flags&^fixedFlagPrerelease != 0 {
+		return fmt.Errorf("Invalid flags %#x in fixed version", flags)
+	}
+	var nums [3]uint64
+	for i := range nums {
+		for _, c := range b[i*fixedComponentSize : (i+1)*fixedComponentSize] {
+			nums[i] = nums[i]<<8 | uint64(c)
+		}
+	}
+	*v = Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}
+	if b[fixedFlagsIndex]&fixedFlagPrerelease != 0 {
+		*v = v.LowestPrerelease()
+	}
+	return nil
+}
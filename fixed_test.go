@@ -0,0 +1,55 @@
+package semver
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFixedRoundTrip(t *testing.T) {
+	tests := []struct {
+		v string
+		o string
+	}{
+		{"0.0.0", "0.0.0"},
+		{"1.2.3", "1.2.3"},
+		{"1.2.3+build.5", "1.2.3"},
+		{"1.2.3-rc.1", "1.2.3-0"},
+		{"1099511627775.1099511627775.1099511627775", "1099511627775.1099511627775.1099511627775"},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.v)
+		b, err := v.MarshalFixed()
+		if err != nil {
+			t.Errorf("Unexpected error marshaling %q: %s", tc.v, err)
+			continue
+		}
+		var o Version
+		if err := o.UnmarshalFixed(b); err != nil {
+			t.Errorf("Unexpected error unmarshaling %q: %s", tc.v, err)
+		} else if o.String() != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.v, tc.o, o)
+		}
+	}
+
+	// The bytes of stable versions sort by precedence
+	a, _ := MustParse("1.255.0").MarshalFixed()
+	b, _ := MustParse("1.256.0").MarshalFixed()
+	if bytes.Compare(a[:], b[:]) >= 0 {
+		t.Errorf("Expected %x to sort before %x", a, b)
+	}
+}
+
+func TestFixedOverflow(t *testing.T) {
+	for _, s := range []string{"1099511627776.0.0", "0.1099511627776.0", "0.0.18446744073709551615"} {
+		if _, err := MustParse(s).MarshalFixed(); err == nil {
+			t.Errorf("Expected error marshaling %q, got none", s)
+		}
+	}
+
+	var v Version
+	var b [16]byte
+	b[15] = 0x80
+	if err := v.UnmarshalFixed(b); err == nil {
+		t.Errorf("Expected error for unknown flags, got none")
+	}
+}
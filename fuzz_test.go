@@ -0,0 +1,61 @@
+//go:build go1.18
+// +build go1.18
+
+package semver
+
+import (
+	"testing"
+)
+
+var rangeFuzzSeeds = []string{
+	">1.2.3", ">=1.2.3 <2.0.0", "!=1.2.3", "!1.2.3", "==1.2.3", "1.x", "1.2.x",
+	"x", "*", "1.* || >=2.0.* <2.2.*", "~10.1.2", "~7.x || ~8.x || ~9.x",
+	"^10.14.1 || ^8.15.0", "^x", "1 - 3", "v1 - v3", "1.2 - 3.4", ">4 <3",
+	"^0.2.3", "=v1.2.3", ">=8.9.1 <9.0", "", "string", "fo.ob.ar.x", ">>1.2.3",
+}
+
+func FuzzParse(f *testing.F) {
+	for _, test := range formatTests {
+		f.Add(test.result)
+	}
+	for _, test := range tolerantFormatTests {
+		f.Add(test.result)
+	}
+	for _, test := range wrongformatTests {
+		f.Add(test.str)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, parse := range []func(string) (Version, error){Parse, ParseTolerant} {
+			v, err := parse(s)
+			if err != nil {
+				continue
+			}
+			if err := v.Validate(); err != nil {
+				t.Fatalf("Parsed version %q from %q does not validate: %s", v, s, err)
+			}
+			v2, err := Parse(v.String())
+			if err != nil {
+				t.Fatalf("Parsed version %q from %q does not re-parse: %s", v, s, err)
+			}
+			if v2.String() != v.String() || v2.Compare(v) != 0 {
+				t.Fatalf("Re-parsed version %q from %q is not equal to %q", v2, s, v)
+			}
+		}
+	})
+}
+
+func FuzzParseRange(f *testing.F) {
+	for _, s := range rangeFuzzSeeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		r, err := ParseRange(s)
+		if err != nil {
+			return
+		}
+		r(Version{})
+		r(MustParse("1.2.3-rc.1+build"))
+	})
+}
@@ -2,14 +2,19 @@ package semver
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // MarshalJSON implements the encoding/json.Marshaler interface.
+// The version is encoded as its canonical string, e.g. "1.2.3-rc.1+build".
 func (v Version) MarshalJSON() ([]byte, error) {
 	return json.Marshal(v.String())
 }
 
 // UnmarshalJSON implements the encoding/json.Unmarshaler interface.
+// The value must be a JSON string holding a version, which is parsed like
+// Parse. JSON null is rejected like an empty version string, use a *Version
+// for optional versions, which null sets to nil.
 func (v *Version) UnmarshalJSON(data []byte) (err error) {
 	var versionString string
 
@@ -21,3 +26,29 @@ func (v *Version) UnmarshalJSON(data []byte) (err error) {
 
 	return
 }
+
+// FilterJSONStream reads a JSON array of version strings from dec and
+// returns the versions matching r. The array is decoded one element at a
+// time, so it is never held in memory as a whole.
+func (r Range) FilterJSONStream(dec *json.Decoder) ([]Version, error) {
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if d, ok := t.(json.Delim); !ok || d != '[' {
+		return nil, fmt.Errorf("Expected JSON array of versions, got %v", t)
+	}
+	var matching []Version
+	for dec.More() {
+		var v Version
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if r(v) {
+			matching = append(matching, v)
+		}
+	}
+	// closing bracket
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return matching, nil
+}
@@ -2,7 +2,9 @@ package semver
 
 import (
 	"encoding/json"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -47,3 +49,82 @@ func TestJSONUnmarshal(t *testing.T) {
 		t.Fatal("expected JSON unmarshal error, got nil")
 	}
 }
+
+func TestJSONStruct(t *testing.T) {
+	type release struct {
+		Name string
+		V    Version
+		Prev *Version `json:",omitempty"`
+	}
+	tests := []struct {
+		v    string
+		json string
+	}{
+		{"1.2.3", `{"Name":"app","V":"1.2.3"}`},
+		{"1.2.3-rc.1", `{"Name":"app","V":"1.2.3-rc.1"}`},
+		{"1.2.3+build.5", `{"Name":"app","V":"1.2.3+build.5"}`},
+		{"1.2.3-rc.1+build", `{"Name":"app","V":"1.2.3-rc.1+build"}`},
+	}
+	for _, test := range tests {
+		r := release{Name: "app", V: MustParse(test.v)}
+		data, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.json {
+			t.Errorf("JSON marshaled struct not equal: expected %s, got %s", test.json, data)
+		}
+		var o release
+		if err := json.Unmarshal(data, &o); err != nil {
+			t.Fatal(err)
+		}
+		if o.V.String() != test.v || o.Prev != nil {
+			t.Errorf("JSON unmarshaled struct not equal: expected %q, got %q", test.v, o.V)
+		}
+	}
+
+	var o release
+	if err := json.Unmarshal([]byte(`{"V":"1.2.3","Prev":"1.2.2"}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Prev == nil || o.Prev.String() != "1.2.2" {
+		t.Errorf("JSON unmarshaled pointer not equal: expected %q, got %v", "1.2.2", o.Prev)
+	}
+
+	// null is an error for a Version, but nil for a *Version
+	if err := json.Unmarshal([]byte(`{"V":null}`), &o); err == nil {
+		t.Error("expected JSON unmarshal error for null, got nil")
+	}
+	if err := json.Unmarshal([]byte(`{"V":"1.2.3","Prev":null}`), &o); err != nil {
+		t.Fatal(err)
+	} else if o.Prev != nil {
+		t.Errorf("expected nil pointer for null, got %q", o.Prev)
+	}
+	if err := json.Unmarshal([]byte(`{"V":"1.2"}`), &o); err == nil {
+		t.Error("expected JSON unmarshal error for short version, got nil")
+	}
+}
+
+func TestRangeFilterJSONStream(t *testing.T) {
+	r := MustParseRange("^1.2.0")
+	dec := json.NewDecoder(strings.NewReader(`["1.1.0", "1.2.0", "1.9.3-rc.1", "2.0.0", "1.5.0+build"]`))
+	vs, err := r.FilterJSONStream(dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	correct := []Version{MustParse("1.2.0"), MustParse("1.9.3-rc.1"), MustParse("1.5.0+build")}
+	if !reflect.DeepEqual(vs, correct) {
+		t.Fatalf("FilterJSONStream returned wrong versions: %s", vs)
+	}
+
+	vs, err = r.FilterJSONStream(json.NewDecoder(strings.NewReader(`[]`)))
+	if err != nil || len(vs) != 0 {
+		t.Fatalf("expected no versions for empty array, got %s, %v", vs, err)
+	}
+
+	for _, data := range []string{`{"v": "1.2.0"}`, `"1.2.0"`, `["1.2.0", "1.2"]`, `["1.2.0", 3]`, `["1.2.0"`, ``} {
+		if _, err := r.FilterJSONStream(json.NewDecoder(strings.NewReader(data))); err == nil {
+			t.Errorf("expected error for %s, got nil", data)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FromMap builds a Version from a generic map, e.g. decoded from a config file.
+// The "major" key is required, "minor" and "patch" default to 0. Their values
+// may be any integer type, an integral float64 or a numeric string.
+// The optional "pre" and "build" keys may hold a dot separated string, like
+// "rc.1", or a list of identifiers.
+func FromMap(m map[string]interface{}) (Version, error) {
+	v := Version{}
+	if _, ok := m["major"]; !ok {
+		return Version{}, errors.New("Major number is missing")
+	}
+	for _, c := range []struct {
+		key string
+		n   *uint64
+	}{{"major", &v.Major}, {"minor", &v.Minor}, {"patch", &v.Patch}} {
+		val, ok := m[c.key]
+		if !ok {
+			continue
+		}
+		n, err := mapUint(val)
+		if err != nil {
+			return Version{}, fmt.Errorf("Invalid %s number: %s", c.key, err)
+		}
+		*c.n = n
+	}
+
+	pre, err := mapIdentifiers(m["pre"])
+	if err != nil {
+		return Version{}, fmt.Errorf("Invalid prerelease: %s", err)
+	}
+	for _, s := range pre {
+		p, err := NewPRVersion(s)
+		if err != nil {
+			return Version{}, err
+		}
+		v.Pre = append(v.Pre, p)
+	}
+
+	build, err := mapIdentifiers(m["build"])
+	if err != nil {
+		return Version{}, fmt.Errorf("Invalid build meta data: %s", err)
+	}
+	for _, s := range build {
+		b, err := NewBuildVersion(s)
+		if err != nil {
+			return Version{}, err
+		}
+		v.Build = append(v.Build, b)
+	}
+
+	return v, nil
+}
+
+// mapUint converts a numeric map value to uint64.
+func mapUint(val interface{}) (uint64, error) {
+	switch n := val.(type) {
+	case int:
+		return signedUint(int64(n))
+	case int8:
+		return signedUint(int64(n))
+	case int16:
+		return signedUint(int64(n))
+	case int32:
+		return signedUint(int64(n))
+	case int64:
+		return signedUint(n)
+	case uint:
+		return uint64(n), nil
+	case uint8:
+		return uint64(n), nil
+	case uint16:
+		return uint64(n), nil
+	case uint32:
+		return uint64(n), nil
+	case uint64:
+		return n, nil
+	case float32:
+		return floatUint(float64(n))
+	case float64:
+		return floatUint(n)
+	case string:
+		if !containsOnly(n, numbers) || len(n) == 0 {
+			return 0, fmt.Errorf("Invalid character(s) found in %q", n)
+		}
+		if hasLeadingZeroes(n) {
+			return 0, fmt.Errorf("Number must not contain leading zeroes %q", n)
+		}
+		return strconv.ParseUint(n, 10, 64)
+	}
+	return 0, fmt.Errorf("Can not convert %T to a number", val)
+}
+
+func signedUint(n int64) (uint64, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("Number must not be negative %d", n)
+	}
+	return uint64(n), nil
+}
+
+func floatUint(f float64) (uint64, error) {
+	if f < 0 || f != math.Trunc(f) || f >= math.MaxUint64 {
+		return 0, fmt.Errorf("Number must be a non-negative integer %v", f)
+	}
+	return uint64(f), nil
+}
+
+// mapIdentifiers converts a map value to a list of identifiers.
+func mapIdentifiers(val interface{}) ([]string, error) {
+	switch ids := val.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		return strings.Split(ids, "."), nil
+	case []string:
+		return ids, nil
+	case []interface{}:
+		out := make([]string, 0, len(ids))
+		for _, id := range ids {
+			switch id := id.(type) {
+			case string:
+				out = append(out, id)
+			default:
+				n, err := mapUint(id)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, strconv.FormatUint(n, 10))
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("Can not convert %T to identifiers", val)
+}
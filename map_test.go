@@ -0,0 +1,48 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestFromMap(t *testing.T) {
+	tests := []struct {
+		m        map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"major": 1, "minor": 2, "patch": 3, "pre": "rc.1", "build": "build.5"}, "1.2.3-rc.1+build.5"},
+		{map[string]interface{}{"major": float64(1), "minor": "2", "patch": uint8(3), "pre": []interface{}{"alpha", float64(1)}, "build": []string{"001"}}, "1.2.3-alpha.1+001"},
+		{map[string]interface{}{"major": int64(1), "minor": 2}, "1.2.0"},
+		{map[string]interface{}{"major": 2}, "2.0.0"},
+		{map[string]interface{}{"major": 2, "pre": "", "build": nil}, "2.0.0"},
+	}
+	for _, test := range tests {
+		v, err := FromMap(test.m)
+		if err != nil {
+			t.Errorf("Error building version from %v: %q", test.m, err)
+		} else if v.String() != test.expected {
+			t.Errorf("Building version from %v, expected %q but got %q", test.m, test.expected, v)
+		}
+	}
+}
+
+func TestFromMapErrors(t *testing.T) {
+	tests := []map[string]interface{}{
+		{},
+		{"minor": 2, "patch": 3},
+		{"major": -1},
+		{"major": 1.5},
+		{"major": "01"},
+		{"major": "one"},
+		{"major": true},
+		{"major": 1, "pre": "rc.01"},
+		{"major": 1, "pre": "rc..1"},
+		{"major": 1, "pre": 7},
+		{"major": 1, "build": "b?"},
+		{"major": 1, "build": []interface{}{true}},
+	}
+	for _, m := range tests {
+		if v, err := FromMap(m); err == nil {
+			t.Errorf("Building version from %v, expected error but got %q", m, v)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package semver
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -30,15 +32,85 @@ var (
 	}
 )
 
+// Operator is the comparison a Comparator applies to its version.
+type Operator string
+
+// Operators supported in ranges.
+const (
+	OpEQ Operator = "="
+	OpNE Operator = "!="
+	OpGT Operator = ">"
+	OpGE Operator = ">="
+	OpLT Operator = "<"
+	OpLE Operator = "<="
+)
+
+// comparator returns the comparison function for o, or nil if o is unknown.
+func (o Operator) comparator() comparator {
+	switch o {
+	case OpEQ:
+		return compEQ
+	case OpNE:
+		return compNE
+	case OpGT:
+		return compGT
+	case OpGE:
+		return compGE
+	case OpLT:
+		return compLT
+	case OpLE:
+		return compLE
+	}
+	return nil
+}
+
+// Comparator is a single operator and version pair of a range, e.g. ">=1.2.3".
+type Comparator struct {
+	Op      Operator
+	Version Version
+}
+
+// Comparator to string. Equality is written as the bare version.
+func (c Comparator) String() string {
+	if c.Op == OpEQ {
+		return c.Version.String()
+	}
+	return string(c.Op) + c.Version.String()
+}
+
+// rangeFunc returns a Range matching the versions satisfying c alone, which
+// compares directly instead of going through a comparator func. It returns
+// nil for an unknown operator.
+func (c Comparator) rangeFunc() Range {
+	o := c.Version
+	switch c.Op {
+	case OpEQ:
+		return func(v Version) bool { return v.Compare(o) == 0 }
+	case OpNE:
+		return func(v Version) bool { return v.Compare(o) != 0 }
+	case OpGT:
+		return func(v Version) bool { return v.Compare(o) == 1 }
+	case OpGE:
+		return func(v Version) bool { return v.Compare(o) >= 0 }
+	case OpLT:
+		return func(v Version) bool { return v.Compare(o) == -1 }
+	case OpLE:
+		return func(v Version) bool { return v.Compare(o) <= 0 }
+	}
+	return nil
+}
+
 type versionRange struct {
 	v Version
 	c comparator
 }
 
 // rangeFunc creates a Range from the given versionRange.
+// A versionRange without comparator, from an unknown operator, matches
+// no version, like in MatchingGroup.
 func (vr *versionRange) rangeFunc() Range {
 	return Range(func(v Version) bool {
-		return vr.c(v, vr.v)
+		return vr.c != nil && vr.c(v, vr.v)
 	})
 }
 
@@ -63,8 +135,305 @@ func (rf Range) AND(f Range) Range {
 	})
 }
 
+// MatchString parses the version s and checks if it satisfies the Range. It
+// returns the error of Parse if s is no valid version.
+func (rf Range) MatchString(s string) (bool, error) {
+	v, err := Parse(s)
+	if err != nil {
+		return false, err
+	}
+	return rf(v), nil
+}
+
+// ErrEmptyRange is returned when parsing an empty or whitespace-only range.
+// Use "*" to match any version.
+var ErrEmptyRange = errors.New("Range string empty")
+
+// RangeExpr is the structured form of a range. It is a list of comparator
+// groups linked by logical OR, where the comparators of each group are
+// linked by logical AND. An empty group matches every version.
+type RangeExpr [][]Comparator
+
+// Range returns a Range matching the same versions as e.
+func (e RangeExpr) Range() Range {
+	if len(e) == 1 && len(e[0]) == 1 {
+		// fast path for the most common ranges like ">=1.2.3"
+		if r := e[0][0].rangeFunc(); r != nil {
+			return r
+		}
+	}
+	orFn := Range(func(Version) bool { return false })
+	for i, group := range e {
+		andFn := Range(func(Version) bool { return true })
+		for j, c := range group {
+			vr := versionRange{v: c.Version, c: c.Op.comparator()}
+			if j == 0 {
+				andFn = vr.rangeFunc()
+			} else { // Combine with existing function
+				andFn = andFn.AND(vr.rangeFunc())
+			}
+		}
+		if i == 0 {
+			orFn = andFn
+		} else {
+			orFn = orFn.OR(andFn)
+		}
+	}
+	return orFn
+}
+
+// RangeExpr to string, e.g. ">=1.0.0 <2.0.0 || 3.0.0".
+// An empty group is written as "*".
+func (e RangeExpr) String() string {
+	groups := make([]string, 0, len(e))
+	for _, group := range e {
+		if len(group) == 0 {
+			groups = append(groups, "*")
+			continue
+		}
+		comps := make([]string, 0, len(group))
+		for _, c := range group {
+			comps = append(comps, c.String())
+		}
+		groups = append(groups, strings.Join(comps, " "))
+	}
+	return strings.Join(groups, " || ")
+}
+
+// NewRange returns a Range accepting the versions between lower and upper.
+// The inclusive flags control whether the bounds themselves are accepted.
+// A zero upper Version means the Range has no upper bound.
+func NewRange(lower Version, lowerInclusive bool, upper Version, upperInclusive bool) Range {
+	lo := Comparator{Op: OpGT, Version: lower}
+	if lowerInclusive {
+		lo.Op = OpGE
+	}
+	group := []Comparator{lo}
+	if !upper.isZero() {
+		hi := Comparator{Op: OpLT, Version: upper}
+		if upperInclusive {
+			hi.Op = OpLE
+		}
+		group = append(group, hi)
+	}
+	return RangeExpr{group}.Range()
+}
+
+// GT returns a Range accepting versions greater than v.
+func GT(v Version) Range {
+	return RangeExpr{{{Op: OpGT, Version: v}}}.Range()
+}
+
+// GTE returns a Range accepting versions greater than or equal to v.
+func GTE(v Version) Range {
+	return RangeExpr{{{Op: OpGE, Version: v}}}.Range()
+}
+
+// LT returns a Range accepting versions less than v.
+func LT(v Version) Range {
+	return RangeExpr{{{Op: OpLT, Version: v}}}.Range()
+}
+
+// LTE returns a Range accepting versions less than or equal to v.
+func LTE(v Version) Range {
+	return RangeExpr{{{Op: OpLE, Version: v}}}.Range()
+}
+
+// PinToMinor returns a Range accepting v and later patches of its minor
+// version, that is ">=v <MAJOR.(MINOR+1).0".
+func PinToMinor(v Version) Range {
+	upper := Version{Major: v.Major, Minor: v.Minor + 1}
+	return RangeExpr{{{Op: OpGE, Version: v}, {Op: OpLT, Version: upper}}}.Range()
+}
+
+// StableFrom returns a Range accepting v and all greater versions, except
+// prereleases.
+func StableFrom(v Version) Range {
+	return GTE(v).AND(func(o Version) bool {
+		return len(o.Pre) == 0
+	})
+}
+
+// PrereleaseRange returns a Range accepting only the prereleases of the core
+// of v, that is ">=MAJOR.MINOR.PATCH-0 <MAJOR.MINOR.PATCH". For 1.2.0 it
+// accepts 1.2.0-rc.1, but neither 1.2.0 nor 1.2.1-rc.1.
+func PrereleaseRange(v Version) Range {
+	return RangeExpr{{{Op: OpGE, Version: v.LowestPrerelease()}, {Op: OpLT, Version: v.Core()}}}.Range()
+}
+
+// EvenMinorsOf returns a Range accepting the versions of the given major
+// version with an even minor version, like the stable releases of projects
+// using odd minors for development.
+func EvenMinorsOf(major uint64) Range {
+	return func(v Version) bool {
+		return v.Major == major && v.Minor%2 == 0
+	}
+}
+
+// OddMinorsOf returns a Range accepting the versions of the given major
+// version with an odd minor version.
+func OddMinorsOf(major uint64) Range {
+	return func(v Version) bool {
+		return v.Major == major && v.Minor%2 == 1
+	}
+}
+
+// Policy is a compatibility policy for RangeFor.
+type Policy int
+
+const (
+	// PatchOnly accepts later patches of the same minor version,
+	// ">=v <MAJOR.(MINOR+1).0".
+	PatchOnly Policy = iota
+	// MinorAndPatch accepts later minor and patch versions of the same
+	// major version, ">=v <(MAJOR+1).0.0".
+	MinorAndPatch
+	// MajorCompatible accepts the versions a caret range accepts, "^v".
+	// Like MinorAndPatch, except for major version 0 where the left-most
+	// non-zero component must not change.
+	MajorCompatible
+	// AnyNewer accepts all versions greater than or equal to v, ">=v".
+	AnyNewer
+)
+
+// RangeFor returns a Range accepting v and all later versions that policy
+// allows. An unknown policy accepts no version.
+func RangeFor(v Version, policy Policy) Range {
+	var upper Version
+	switch policy {
+	case PatchOnly:
+		upper = Version{Major: v.Major, Minor: v.Minor + 1}
+	case MinorAndPatch:
+		upper = Version{Major: v.Major + 1}
+	case MajorCompatible:
+		upper = caretUpper(v)
+	case AnyNewer:
+		return GTE(v)
+	default:
+		return RangeExpr{}.Range()
+	}
+	return RangeExpr{{{Op: OpGE, Version: v}, {Op: OpLT, Version: upper}}}.Range()
+}
+
+// caretUpper returns the exclusive upper bound of the caret range "^v": the
+// next version incrementing the left-most non-zero component of v.
+func caretUpper(v Version) Version {
+	switch {
+	case v.Major > 0:
+		return Version{Major: v.Major + 1}
+	case v.Minor > 0:
+		return Version{Minor: v.Minor + 1}
+	}
+	return Version{Patch: v.Patch + 1}
+}
+
+// CaretCompatible checks if other satisfies the caret range of base, "^base",
+// without building and parsing the range. For major version 0, the left-most
+// non-zero component must not change, so 0.2.5 is compatible with 0.2.3, but
+// 0.3.0 is not.
+func (base Version) CaretCompatible(other Version) bool {
+	return other.GTE(base) && other.LT(caretUpper(base))
+}
+
+// RecommendedRange returns the range a package manager like npm writes for
+// a dependency on v, the caret range "^v". For major version 0 the caret
+// range already narrows to the minor, or the patch for 0.0.x, versions, so
+// "^0.2.3" accepts 0.2.x only. Build metadata is left out.
+func RecommendedRange(v Version) string {
+	return "^" + v.StringNoBuild()
+}
+
+// ExcludeAdvisory adds "!=" exclusions for the bad versions to the range
+// base and returns the combined range, e.g. "^1.2.0 !=1.4.1" for the base
+// "^1.2.0" and the bad version 1.4.1. Each group of the base is only
+// extended by the bad versions it matches, hyphen ranges are expanded to
+// their comparators first. An error is returned if base can not be parsed.
+func ExcludeAdvisory(base string, bad []Version) (string, error) {
+	if _, err := ParseRangeExpr(base); err != nil {
+		return "", err
+	}
+	parts := strings.Split(base, "||")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		// the parts have been validated by ParseRangeExpr
+		groups, _ := parseRangeGroups(part)
+		matches := RangeExpr(groups).Range()
+		// a hyphen range can not be followed by further comparators
+		part = hyphenReplace(rangeRegex, part)
+		excluded := make(map[string]bool)
+		for _, v := range bad {
+			// build meta data has no precedence
+			s := Comparator{Op: OpNE, Version: v.WithoutBuild()}.String()
+			if excluded[s] || !matches(v) {
+				continue
+			}
+			excluded[s] = true
+			part += " " + s
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, " || "), nil
+}
+
+// SecurityBaseline returns a Range accepting the versions at or above the
+// minimum version of a security advisory, ">=minimum". With sameMajor it
+// only accepts versions of the same major version, ">=minimum <(MAJOR+1).0.0".
+func SecurityBaseline(minimum Version, sameMajor bool) Range {
+	if sameMajor {
+		return RangeFor(minimum, MinorAndPatch)
+	}
+	return RangeFor(minimum, AnyNewer)
+}
+
+// SatisfiesAll checks if v satisfies every one of the ranges.
+// It returns true if no ranges are given.
+func SatisfiesAll(v Version, ranges ...Range) bool {
+	for _, r := range ranges {
+		if !r(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// SatisfiesAny checks if v satisfies at least one of the ranges.
+// It returns false if no ranges are given.
+func SatisfiesAny(v Version, ranges ...Range) bool {
+	for _, r := range ranges {
+		if r(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// WhichRanges returns the names of all ranges in named which v satisfies,
+// sorted by name.
+func WhichRanges(v Version, named map[string]Range) []string {
+	var names []string
+	for name, r := range named {
+		if r(v) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RangeForEach calls fn for each version of vs which satisfies r, in the
+// order of vs, without allocating a slice of the matches. It stops as soon
+// as fn returns false.
+func RangeForEach(vs []Version, r Range, fn func(Version) bool) {
+	for _, v := range vs {
+		if r(v) && !fn(v) {
+			return
+		}
+	}
+}
+
 // ParseRange parses a range and returns a Range.
-// If the range could not be parsed an error is returned.
+// If the range could not be parsed an error is returned,
+// ErrEmptyRange if it is empty or consists of whitespace only.
 //
 // Valid ranges are:
 //   - "<1.0.0"
@@ -73,6 +442,11 @@ func (rf Range) AND(f Range) Range {
 //   - ">=1.0.0"
 //   - "1.0.0", "=1.0.0", "==1.0.0"
 //   - "!1.0.0", "!=1.0.0"
+//   - "!=1.x", "!1.2" match every version outside of the X-Range
+//   - "x", "*", ">=x", "<=x" match every version
+//
+// A wildcard major version can not be used with ">" or "<", nor be followed
+// by a minor or patch number, so ">x", "<x" and "x.2.3" are invalid.
 //
 // A Range can consist of multiple ranges separated by space:
 // Ranges can be linked by logical AND:
@@ -88,45 +462,383 @@ func (rf Range) AND(f Range) Range {
 //
 //  - `>1.0.0 <2.0.0 || >3.0.0 !4.2.1` would match `1.2.3`, `1.9.9`, `3.1.1`, but not `4.2.1`, `2.1.1`
 func ParseRange(s string) (Range, error) {
-	var expandedParts [][]string
-	// split on boolean or ||
-	orParts := regexp.MustCompile("\\s*\\|\\|\\s*").Split(s, -1)
-	for _, part := range orParts {
-		parsed := parseRange(part)
-		if len(parsed) > 0 {
-			expandedParts = append(expandedParts, parseRange(strings.TrimSpace(part)))
+	e, err := ParseRangeExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	return e.Range(), nil
+}
+
+// RangeOptions configures optional behavior of ParseRangeWithOptions.
+type RangeOptions struct {
+	// WildcardStableOnly makes wildcard patch ranges like "1.2.x", "1.2" or
+	// "1.x" match stable versions only. Without it they also match the
+	// prereleases of the versions they cover, e.g. 1.2.3-beta for "1.2.x",
+	// like for ParseRange.
+	WildcardStableOnly bool
+
+	// EmptyMeansAny makes an empty or whitespace-only range match every
+	// version like "*", as some registries treat a missing constraint.
+	// Without it such ranges are rejected with ErrEmptyRange.
+	EmptyMeansAny bool
+
+	// Strict rejects every token of the range which is not a comparator
+	// with a complete version or X-Range, like ">=1.2.3", "^1.2" or "1.x",
+	// instead of passing it on to the more forgiving expansion of ParseRange.
+	// It rejects e.g. "x.x.x-foo", whose prerelease would be dropped, and
+	// "<*", which would match every version.
+	Strict bool
+}
+
+// ParseRangeWithOptions parses a range like ParseRange, with the optional
+// behavior configured in opts. The zero RangeOptions behave exactly like
+// ParseRange.
+func ParseRangeWithOptions(s string, opts RangeOptions) (Range, error) {
+	if opts.EmptyMeansAny && len(strings.TrimSpace(s)) == 0 {
+		s = "*"
+	}
+	if opts.Strict {
+		if err := checkStrictRange(s); err != nil {
+			return nil, err
+		}
+	}
+	e, err := ParseRangeExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.WildcardStableOnly {
+		return e.Range(), nil
+	}
+	var r Range
+	for _, part := range strings.Split(s, "||") {
+		// the parts have been validated by ParseRangeExpr
+		groups, _ := parseRangeGroups(strings.TrimSpace(part))
+		gr := RangeExpr(groups).Range()
+		if hasWildcardPatch(part) {
+			gr = gr.AND(func(v Version) bool {
+				return len(v.Pre) == 0
+			})
+		}
+		if r == nil {
+			r = gr
+		} else {
+			r = r.OR(gr)
 		}
 	}
+	return r, nil
+}
 
-	var orFn Range
-	for _, p := range expandedParts {
-		var andFn Range
-		for _, ap := range p {
-			opStr, vStr, err := splitComparatorVersion(ap)
-			if err != nil {
-				return nil, err
+// strictComparatorRegex matches a single comparator of a strict range: an
+// optional operator followed by a version, or an X-Range without prerelease
+// and build meta data.
+var strictComparatorRegex = regexp.MustCompile(`^(<=|>=|<|>|==|=|!=|!|~>|~|\^)?` +
+	`v?(?:(?:0|[1-9]\d*)\.(?:0|[1-9]\d*)\.(?:0|[1-9]\d*)` +
+	`(?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?` +
+	`|((?:0|[1-9]\d*|[xX*])(?:\.(?:0|[1-9]\d*|[xX*])){0,2}))$`)
+
+// checkStrictRange checks that every token of the range s is a comparator
+// for RangeOptions.Strict. An operator may be separated from its version by
+// whitespace, and the versions of a hyphen range by " - ".
+func checkStrictRange(s string) error {
+	for _, part := range strings.Split(s, "||") {
+		var tokens []string
+		fields := strings.Fields(part)
+		for i := 0; i < len(fields); i++ {
+			tok := fields[i]
+			if strings.Trim(tok, "<>=!~^") == "" && tok != "" && i+1 < len(fields) {
+				i++
+				tok += fields[i]
 			}
-			vr, err := buildVersionRange(opStr, vStr)
-			if err != nil {
-				return nil, fmt.Errorf("Could not parse Range %q: %s", ap, err)
+			tokens = append(tokens, tok)
+		}
+		hyphen := len(tokens) == 3 && tokens[1] == "-"
+		if hyphen {
+			tokens = []string{tokens[0], tokens[2]}
+		}
+		for _, tok := range tokens {
+			match := strictComparatorRegex.FindStringSubmatch(tok)
+			if match == nil {
+				return fmt.Errorf("Invalid comparator %q in range %q", tok, strings.TrimSpace(s))
 			}
-			rf := vr.rangeFunc()
-
-			// Set function
-			if andFn == nil {
-				andFn = rf
-			} else { // Combine with existing function
-				andFn = andFn.AND(rf)
+			if hyphen && match[1] != "" {
+				return fmt.Errorf("Invalid comparator %q in range %q, the versions of a hyphen range can not have an operator", tok, strings.TrimSpace(s))
+			}
+			if (match[1] == "<" || match[1] == ">") && match[2] != "" && isX(strings.SplitN(match[2], ".", 2)[0]) {
+				return fmt.Errorf("Invalid comparator %q in range %q, a wildcard can not follow > or <", tok, strings.TrimSpace(s))
 			}
 		}
-		if orFn == nil {
-			orFn = andFn
+	}
+	return nil
+}
+
+// hasWildcardPatch checks if one of the comparators of the AND group s is
+// an X-Range with a wildcard patch, but a fixed major version, like "1.2.x".
+func hasWildcardPatch(s string) bool {
+	for _, comp := range strings.Fields(strings.Replace(s, "==", "=", -1)) {
+		match := rangeRegex["XRANGE"].FindStringSubmatch(comp)
+		if match != nil && (match[1] == "" || match[1] == "=") && !isX(match[2]) && isX(match[4]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRangeExpr parses a range like ParseRange, but returns its structured
+// form rather than a Range.
+func ParseRangeExpr(s string) (RangeExpr, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, ErrEmptyRange
+	}
+	e := make(RangeExpr, 0, strings.Count(s, "||")+1)
+	// split on boolean or ||
+	for start := 0; start <= len(s); {
+		end := strings.Index(s[start:], "||")
+		if end == -1 {
+			end = len(s)
 		} else {
-			orFn = orFn.OR(andFn)
+			end += start
+		}
+		part := strings.TrimSpace(s[start:end])
+		start = end + 2
+		if len(part) == 0 {
+			return nil, fmt.Errorf("Could not parse Range %q: empty operand of \"||\", use \"*\" to match any version", strings.TrimSpace(s))
 		}
 
+		groups, err := parseRangeGroups(part)
+		if err != nil {
+			return nil, err
+		}
+		e = append(e, groups...)
 	}
-	return orFn, nil
+	return e, nil
+}
+
+// NormalizeRange parses the range s and returns its canonical form, e.g.
+// ">=1.2.3" for both ">= 1.2.3" and ">=v1.2.3". Equivalent spellings
+// of the same comparators, like "==1.2.3", "=1.2.3" and "v1.2.3", are written
+// as the bare version. X-Ranges, tilde and caret ranges are expanded to their
+// comparators, so the result is suitable as a cache key.
+func NormalizeRange(s string) (string, error) {
+	e, err := ParseRangeExpr(s)
+	if err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+// parseRangeGroups parses one OR part of a range. It usually returns a
+// single AND group, but negated X-Ranges like "!=1.x" split it into one
+// group for the versions below and one for those above the X-Range.
+func parseRangeGroups(part string) ([][]Comparator, error) {
+	parsed := parseRange(part)
+	groups := [][]Comparator{make([]Comparator, 0, len(parsed))}
+	for _, ap := range parsed {
+		opStr, vStr, err := splitComparatorVersion(ap)
+		if err != nil {
+			return nil, err
+		}
+		alts, ok, err := negateXRange(opStr, vStr)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse Range %q: %s", ap, err)
+		}
+		if ok {
+			split := make([][]Comparator, 0, len(groups)*len(alts))
+			for _, group := range groups {
+				for _, alt := range alts {
+					g := make([]Comparator, 0, len(group)+1)
+					split = append(split, append(append(g, group...), alt))
+				}
+			}
+			groups = split
+			continue
+		}
+		vr, err := buildVersionRange(opStr, vStr)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse Range %q: %s", ap, err)
+		}
+		for i := range groups {
+			groups[i] = append(groups[i], Comparator{Op: parseOperator(opStr), Version: vr.v})
+		}
+	}
+	return groups, nil
+}
+
+// negateXRange returns the alternative comparators matching the versions
+// outside of the X-Range vStr if it is negated by opStr, e.g. "<1.0.0" and
+// ">=2.0.0" for "!=1.x". It returns false if vStr is no X-Range or opStr no
+// negation.
+func negateXRange(opStr, vStr string) ([]Comparator, bool, error) {
+	if parseOperator(opStr) != OpNE {
+		return nil, false, nil
+	}
+	match := rangeRegex["XRANGE"].FindStringSubmatch(vStr)
+	if match == nil || match[1] != "" || !isX(match[4]) {
+		return nil, false, nil
+	}
+	if isX(match[2]) {
+		return nil, false, fmt.Errorf("Invalid wildcard major version in %q, it can not be negated", vStr)
+	}
+	var alts []Comparator
+	for _, ap := range parseRange(vStr) {
+		opStr, vStr, err := splitComparatorVersion(ap)
+		if err != nil {
+			return nil, false, err
+		}
+		vr, err := buildVersionRange(opStr, vStr)
+		if err != nil {
+			return nil, false, err
+		}
+		switch parseOperator(opStr) {
+		case OpGE:
+			alts = append(alts, Comparator{Op: OpLT, Version: vr.v})
+		case OpLT:
+			alts = append(alts, Comparator{Op: OpGE, Version: vr.v})
+		}
+	}
+	return alts, true, nil
+}
+
+// ParseCargoRange parses a version requirement in the syntax of Rust's Cargo
+// and returns a Range. Comparators are separated by commas and linked by
+// logical AND:
+//   - ">=1.2.0, <1.5.0"
+//
+// Unlike ParseRange, a bare version is a caret requirement, so "1.2.3"
+// means "^1.2.3". Use "=1.2.3" to require an exact version.
+func ParseCargoRange(s string) (Range, error) {
+	if strings.Contains(s, "||") {
+		return nil, fmt.Errorf("Could not parse Cargo range %q: logical OR is not supported", s)
+	}
+	parts := strings.Split(s, ",")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			return nil, fmt.Errorf("Could not parse Cargo range %q: empty requirement", s)
+		}
+		if unicode.IsDigit(rune(part[0])) {
+			part = "^" + part
+		}
+		parts[i] = part
+	}
+	return ParseRange(strings.Join(parts, " "))
+}
+
+// naturalOperators maps the phrases of ParseNaturalRange to operators.
+var naturalOperators = []struct {
+	phrase string
+	op     Operator
+}{
+	{"greater than or equal to ", OpGE},
+	{"less than or equal to ", OpLE},
+	{"greater than ", OpGT},
+	{"less than ", OpLT},
+	{"at least ", OpGE},
+	{"at most ", OpLE},
+	{"exactly ", OpEQ},
+	{"not ", OpNE},
+}
+
+// ParseNaturalRange parses a range written in words and returns a Range.
+// Valid ranges are:
+//   - "greater than 1.0.0", "greater than or equal to 1.0.0", "at least 1.0.0"
+//   - "less than 1.0.0", "less than or equal to 1.0.0", "at most 1.0.0"
+//   - "exactly 1.0.0", "not 1.0.0"
+//   - "between 1.0.0 and 2.0.0", which includes both versions
+//
+// The words are case insensitive.
+func ParseNaturalRange(s string) (Range, error) {
+	words := strings.Join(strings.Fields(s), " ")
+	if rest, ok := trimPhrase(words, "between "); ok {
+		fields := strings.Fields(rest)
+		if len(fields) != 3 || !strings.EqualFold(fields[1], "and") || strings.Contains(rest, "|") {
+			return nil, fmt.Errorf("Could not parse natural range %q: expected two versions", s)
+		}
+		return ParseRange(">=" + fields[0] + " <=" + fields[2])
+	}
+	for _, n := range naturalOperators {
+		if rest, ok := trimPhrase(words, n.phrase); ok {
+			if strings.ContainsAny(rest, " |") {
+				return nil, fmt.Errorf("Could not parse natural range %q: expected a single version", s)
+			}
+			return ParseRange(string(n.op) + rest)
+		}
+	}
+	return nil, fmt.Errorf("Could not parse natural range %q", s)
+}
+
+// trimPhrase removes the leading phrase from s, ignoring case. It returns
+// false if s does not start with phrase.
+func trimPhrase(s, phrase string) (string, bool) {
+	if len(s) < len(phrase) || !strings.EqualFold(s[:len(phrase)], phrase) {
+		return s, false
+	}
+	return s[len(phrase):], true
+}
+
+// RangeSyntax selects the dialect understood by ParseRangeWithSyntax.
+type RangeSyntax int
+
+const (
+	// SyntaxNPM is the syntax of ParseRange, comparators are separated by
+	// spaces and linked by logical AND.
+	SyntaxNPM RangeSyntax = iota
+	// SyntaxComposer is the syntax of PHP's Composer, comparators are
+	// separated by commas or spaces and linked by logical AND.
+	SyntaxComposer
+)
+
+// composerTildeRegex matches a Composer tilde constraint with a major and
+// minor version only, which unlike in npm allows every later minor version.
+var composerTildeRegex = regexp.MustCompile(`^~v?(\d+)\.(\d+)$`)
+
+// ParseRangeWithSyntax parses a range in the given syntax and returns a
+// Range. In both syntaxes groups of comparators are linked by logical OR
+// with "||". SyntaxComposer also accepts a single "|" for OR and commas
+// between comparators, and follows Composer's tilde rule, so "~1.2" means
+// ">=1.2.0 <2.0.0" instead of ">=1.2.0 <1.3.0". Other comparators have their
+// npm meaning.
+//   - ">=1.0, <2.0 | >=3.0"
+func ParseRangeWithSyntax(s string, syntax RangeSyntax) (Range, error) {
+	switch syntax {
+	case SyntaxNPM:
+		return ParseRange(s)
+	case SyntaxComposer:
+		groups := strings.Split(strings.Replace(s, "||", "|", -1), "|")
+		for i, group := range groups {
+			if strings.TrimSpace(group) == "" && len(groups) > 1 {
+				return nil, fmt.Errorf("Could not parse Composer range %q: empty constraint", s)
+			}
+			parts := strings.Split(group, ",")
+			for j, part := range parts {
+				fields := strings.Fields(part)
+				if len(fields) == 0 && len(parts) > 1 {
+					return nil, fmt.Errorf("Could not parse Composer range %q: empty constraint", s)
+				}
+				var out []string
+				for k := 0; k < len(fields); k++ {
+					f := fields[k]
+					if f == "~" && k+1 < len(fields) {
+						k++
+						f += fields[k]
+					}
+					if m := composerTildeRegex.FindStringSubmatch(f); m != nil {
+						f = ">=" + m[1] + "." + m[2] + ".0 <" + increment(m[1]) + ".0.0"
+					}
+					out = append(out, f)
+				}
+				parts[j] = strings.Join(out, " ")
+			}
+			groups[i] = strings.Join(parts, " ")
+		}
+		return ParseRange(strings.Join(groups, " || "))
+	}
+	return nil, fmt.Errorf("Invalid range syntax %d", syntax)
+}
+
+// reversedOperators maps common typos of operators to the correct ones.
+var reversedOperators = map[string]string{
+	"=<": "<=",
+	"=>": ">=",
 }
 
 // buildVersionRange takes a slice of 2: operator and version
@@ -134,6 +846,9 @@ func ParseRange(s string) (Range, error) {
 func buildVersionRange(opStr, vStr string) (*versionRange, error) {
 	c := parseComparator(opStr)
 	if c == nil {
+		if fix, ok := reversedOperators[opStr]; ok {
+			return nil, fmt.Errorf("Could not parse comparator %q in %q, did you mean %q?", opStr, strings.Join([]string{opStr, vStr}, ""), fix)
+		}
 		return nil, fmt.Errorf("Could not parse comparator %q in %q", opStr, strings.Join([]string{opStr, vStr}, ""))
 	}
 	v, err := Parse(vStr)
@@ -165,6 +880,9 @@ func splitComparatorVersion(s string) (string, string, error) {
 	if s == "x" {
 		return "", "x", nil
 	}
+	if match := rangeRegex["XRANGE"].FindStringSubmatch(s); match != nil && isX(match[2]) {
+		return "", "", fmt.Errorf("Invalid wildcard major version in %q, it can not follow > or < or precede a minor or patch number", s)
+	}
 	i := strings.IndexFunc(s, isVersionString)
 	if i == -1 {
 		return "", "", fmt.Errorf("Could not get version from string: %q", s)
@@ -177,28 +895,33 @@ func isVersionString(c rune) bool {
 }
 
 func parseComparator(s string) comparator {
+	return parseOperator(s).comparator()
+}
+
+// parseOperator normalizes an operator string, returning "" if it is unknown.
+func parseOperator(s string) Operator {
 	switch s {
 	case "==":
 		fallthrough
 	case "":
 		fallthrough
 	case "=":
-		return compEQ
+		return OpEQ
 	case ">":
-		return compGT
+		return OpGT
 	case ">=":
-		return compGE
+		return OpGE
 	case "<":
-		return compLT
+		return OpLT
 	case "<=":
-		return compLE
+		return OpLE
 	case "!":
 		fallthrough
 	case "!=":
-		return compNE
+		return OpNE
 	}
 
-	return nil
+	return ""
 }
 
 // MustParseRange is like ParseRange but panics if the range cannot be parsed.
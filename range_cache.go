@@ -0,0 +1,56 @@
+package semver
+
+import (
+	"sync"
+)
+
+// rangeCache holds the Ranges compiled by CompileRange, keyed by their input.
+var rangeCache sync.Map
+
+// CompileRange is like ParseRange, but caches the Range of every successfully
+// parsed input, so repeated calls with the same string return the previously
+// compiled Range without parsing it again. It is safe for concurrent use.
+//
+// The cache is never evicted and grows with every distinct input, so it
+// should only be used for a bounded set of range strings, e.g. the
+// constraints of a dependency graph. Inputs which fail to parse are not
+// cached.
+func CompileRange(s string) (Range, error) {
+	if r, ok := rangeCache.Load(s); ok {
+		return r.(Range), nil
+	}
+	r, err := ParseRange(s)
+	if err != nil {
+		return nil, err
+	}
+	rangeCache.Store(s, r)
+	return r, nil
+}
+
+// ResetRangeCache removes all Ranges compiled by CompileRange from the cache.
+func ResetRangeCache() {
+	rangeCache.Range(func(key, _ interface{}) bool {
+		rangeCache.Delete(key)
+		return true
+	})
+}
+
+// CompileRanges compiles each of the range strings with CompileRange, so
+// identical constraints are only parsed once. The returned slices are of the
+// same length as ss; for every input either its Range or its error is set.
+func CompileRanges(ss []string) ([]Range, []error) {
+	ranges := make([]Range, len(ss))
+	errs := make([]error, len(ss))
+	failed := make(map[string]error)
+	for i, s := range ss {
+		if err, ok := failed[s]; ok {
+			errs[i] = err
+			continue
+		}
+		ranges[i], errs[i] = CompileRange(s)
+		if errs[i] != nil {
+			failed[s] = errs[i]
+		}
+	}
+	return ranges, errs
+}
@@ -0,0 +1,121 @@
+package semver
+
+import (
+	"testing"
+)
+
+func cachedRanges() int {
+	n := 0
+	rangeCache.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestCompileRange(t *testing.T) {
+	ResetRangeCache()
+	defer ResetRangeCache()
+
+	r, err := CompileRange(">=1.0.0 <2.0.0")
+	if err != nil {
+		t.Fatalf("Unexpected error %q", err)
+	}
+	if !r(MustParse("1.2.3")) || r(MustParse("2.0.0")) {
+		t.Errorf("Unexpected range behavior on CompileRange")
+	}
+	if _, err := CompileRange(">=1.0.0 <2.0.0"); err != nil {
+		t.Fatalf("Unexpected error %q", err)
+	}
+	if n := cachedRanges(); n != 1 {
+		t.Errorf("Expected 1 cached range, got %d", n)
+	}
+
+	if _, err := CompileRange("invalid"); err == nil {
+		t.Errorf("Expected error, got none")
+	}
+	if n := cachedRanges(); n != 1 {
+		t.Errorf("Expected invalid range not to be cached, got %d cached ranges", n)
+	}
+
+	ResetRangeCache()
+	if n := cachedRanges(); n != 0 {
+		t.Errorf("Expected empty cache after reset, got %d cached ranges", n)
+	}
+}
+
+func TestCompileRanges(t *testing.T) {
+	ResetRangeCache()
+	defer ResetRangeCache()
+
+	ss := []string{"^1.2.0", ">=2.0.0", "^1.2.0", "invalid", "^1.2.0", "invalid"}
+	ranges, errs := CompileRanges(ss)
+	if len(ranges) != len(ss) || len(errs) != len(ss) {
+		t.Fatalf("Expected %d ranges and errors, got %d and %d", len(ss), len(ranges), len(errs))
+	}
+	for i, s := range ss {
+		if s == "invalid" {
+			if errs[i] == nil || ranges[i] != nil {
+				t.Errorf("Expected error for %q at %d, got none", s, i)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("Unexpected error for %q at %d: %q", s, i, errs[i])
+		}
+	}
+	if !ranges[4](MustParse("1.5.0")) || ranges[4](MustParse("2.0.0")) {
+		t.Errorf("Unexpected range behavior on CompileRanges")
+	}
+	if !ranges[1](MustParse("2.0.0")) {
+		t.Errorf("Unexpected range behavior on CompileRanges")
+	}
+	if n := cachedRanges(); n != 2 {
+		t.Errorf("Expected 2 cached ranges, got %d", n)
+	}
+}
+
+var manifestRanges = []string{
+	"^1.2.0", ">=2.0.0 <3.0.0", "~1.4.2", "^1.2.0", "^1.2.0", ">=2.0.0 <3.0.0",
+	"1.x || >=3.0.0", "^1.2.0", "~1.4.2", "1.x || >=3.0.0",
+}
+
+func BenchmarkRangeParseManifest(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, s := range manifestRanges {
+			ParseRange(s)
+		}
+	}
+}
+
+func BenchmarkRangeCompileManifest(b *testing.B) {
+	ResetRangeCache()
+	defer ResetRangeCache()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		CompileRanges(manifestRanges)
+	}
+}
+
+func BenchmarkRangeParseRepeated(b *testing.B) {
+	const VERSION = ">=1.0.0 <2.0.0 || >=3.0.1 <4.0.0 !=3.0.3 || >=5.0.0"
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ParseRange(VERSION)
+	}
+}
+
+func BenchmarkRangeCompileRepeated(b *testing.B) {
+	const VERSION = ">=1.0.0 <2.0.0 || >=3.0.1 <4.0.0 !=3.0.3 || >=5.0.0"
+	ResetRangeCache()
+	defer ResetRangeCache()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		CompileRange(VERSION)
+	}
+}
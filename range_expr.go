@@ -0,0 +1,562 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// endpoint is one end of an interval. An endpoint which is not set is
+// unbounded.
+type endpoint struct {
+	v         Version
+	inclusive bool
+	set       bool
+}
+
+// compareLower compares two lower endpoints by the first version they admit.
+func compareLower(a, b endpoint) int {
+	switch {
+	case !a.set && !b.set:
+		return 0
+	case !a.set:
+		return -1
+	case !b.set:
+		return 1
+	}
+	if c := a.v.Compare(b.v); c != 0 {
+		return c
+	}
+	if a.inclusive == b.inclusive {
+		return 0
+	} else if a.inclusive {
+		return -1
+	}
+	return 1
+}
+
+// compareUpper compares two upper endpoints by the last version they admit.
+func compareUpper(a, b endpoint) int {
+	switch {
+	case !a.set && !b.set:
+		return 0
+	case !a.set:
+		return 1
+	case !b.set:
+		return -1
+	}
+	if c := a.v.Compare(b.v); c != 0 {
+		return c
+	}
+	if a.inclusive == b.inclusive {
+		return 0
+	} else if a.inclusive {
+		return 1
+	}
+	return -1
+}
+
+// interval is the set of versions between lo and hi, except the excluded
+// versions. Excluded versions are sorted and lie strictly between lo and hi.
+type interval struct {
+	lo, hi   endpoint
+	excluded []Version
+}
+
+// empty checks if no version lies between the endpoints of iv.
+func (iv interval) empty() bool {
+	if !iv.lo.set || !iv.hi.set {
+		return false
+	}
+	c := iv.lo.v.Compare(iv.hi.v)
+	return c > 0 || (c == 0 && !(iv.lo.inclusive && iv.hi.inclusive))
+}
+
+// inside checks if v lies strictly between the endpoints of iv.
+func (iv interval) inside(v Version) bool {
+	return (!iv.lo.set || v.GT(iv.lo.v)) && (!iv.hi.set || v.LT(iv.hi.v))
+}
+
+// contains checks if v is a member of iv.
+func (iv interval) contains(v Version) bool {
+	if iv.lo.set && (v.LT(iv.lo.v) || (v.EQ(iv.lo.v) && !iv.lo.inclusive)) {
+		return false
+	}
+	if iv.hi.set && (v.GT(iv.hi.v) || (v.EQ(iv.hi.v) && !iv.hi.inclusive)) {
+		return false
+	}
+	for _, x := range iv.excluded {
+		if v.EQ(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// exclude removes the given versions from iv. Exclusions on an inclusive
+// endpoint turn it exclusive, exclusions outside of iv are dropped.
+// It returns false if iv becomes empty.
+func (iv *interval) exclude(vs []Version) bool {
+	for _, x := range vs {
+		if iv.lo.set && x.EQ(iv.lo.v) {
+			iv.lo.inclusive = false
+		}
+		if iv.hi.set && x.EQ(iv.hi.v) {
+			iv.hi.inclusive = false
+		}
+	}
+	if iv.empty() {
+		return false
+	}
+	for _, x := range vs {
+		if iv.inside(x) {
+			iv.excluded = append(iv.excluded, x)
+		}
+	}
+	sort.Sort(Versions(iv.excluded))
+	excluded := iv.excluded[:0]
+	for i, x := range iv.excluded {
+		if i == 0 || x.NE(iv.excluded[i-1]) {
+			excluded = append(excluded, x)
+		}
+	}
+	iv.excluded = excluded
+	return true
+}
+
+// groupInterval computes the interval matched by an AND group of
+// comparators. It returns false if the group matches no version.
+func groupInterval(group []Comparator) (interval, bool) {
+	var iv interval
+	var excluded []Version
+	for _, c := range group {
+		lo := endpoint{v: c.Version, inclusive: c.Op != OpGT, set: true}
+		hi := endpoint{v: c.Version, inclusive: c.Op != OpLT, set: true}
+		switch c.Op {
+		case OpGT, OpGE:
+			hi.set = false
+		case OpLT, OpLE:
+			lo.set = false
+		case OpNE:
+			excluded = append(excluded, c.Version)
+			continue
+		}
+		if compareLower(lo, iv.lo) > 0 {
+			iv.lo = lo
+		}
+		if compareUpper(hi, iv.hi) < 0 {
+			iv.hi = hi
+		}
+	}
+	if !iv.exclude(excluded) {
+		return interval{}, false
+	}
+	return iv, true
+}
+
+// comparators converts iv back into an AND group of comparators.
+func (iv interval) comparators() []Comparator {
+	group := []Comparator{}
+	if iv.lo.set && iv.hi.set && iv.lo.v.EQ(iv.hi.v) {
+		return append(group, Comparator{Op: OpEQ, Version: iv.lo.v})
+	}
+	if iv.lo.set {
+		op := OpGT
+		if iv.lo.inclusive {
+			op = OpGE
+		}
+		group = append(group, Comparator{Op: op, Version: iv.lo.v})
+	}
+	if iv.hi.set {
+		op := OpLT
+		if iv.hi.inclusive {
+			op = OpLE
+		}
+		group = append(group, Comparator{Op: op, Version: iv.hi.v})
+	}
+	for _, x := range iv.excluded {
+		group = append(group, Comparator{Op: OpNE, Version: x})
+	}
+	return group
+}
+
+// intervals returns the non-empty intervals of the groups of e.
+func (e RangeExpr) intervals() []interval {
+	ivs := make([]interval, 0, len(e))
+	for _, group := range e {
+		if iv, ok := groupInterval(group); ok {
+			ivs = append(ivs, iv)
+		}
+	}
+	return ivs
+}
+
+// unionIntervals merges overlapping and adjacent intervals and returns the
+// minimal set of disjoint intervals, sorted by their lower endpoint.
+func unionIntervals(ivs []interval) []interval {
+	sorted := make([]interval, len(ivs))
+	copy(sorted, ivs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareLower(sorted[i].lo, sorted[j].lo) < 0
+	})
+
+	var out []interval
+	for _, iv := range sorted {
+		if len(out) > 0 {
+			if merged, ok := joinIntervals(out[len(out)-1], iv); ok {
+				out[len(out)-1] = merged
+				continue
+			}
+		}
+		out = append(out, iv)
+	}
+	return out
+}
+
+// successor returns the lowest version above v by precedence, e.g. 1.0.1-0
+// for 1.0.0 or 1.0.0-rc.0 for 1.0.0-rc. No version lies between the two.
+func successor(v Version) Version {
+	if len(v.Pre) == 0 {
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}.LowestPrerelease()
+	}
+	s := v.Core()
+	s.Pre = append(append([]PRVersion{}, v.Pre...), PRVersion{VersionNum: 0, IsNum: true})
+	return s
+}
+
+// joinIntervals returns the union of a and b as a single interval if
+// possible. The lower endpoint of a must not be above the one of b. Bounds
+// without a version between them are adjacent, like <=1.0.0 and >=1.0.1-0.
+func joinIntervals(a, b interval) (interval, bool) {
+	var gap []Version
+	if a.hi.set && b.lo.set {
+		switch c := a.hi.v.Compare(b.lo.v); {
+		case c < 0:
+			if !a.hi.inclusive || !b.lo.inclusive || !b.lo.v.EQ(successor(a.hi.v)) {
+				return interval{}, false
+			}
+		case c == 0 && !a.hi.inclusive && !b.lo.inclusive:
+			// a and b only leave out the version they touch at
+			gap = append(gap, b.lo.v)
+		}
+	}
+
+	merged := interval{lo: a.lo, hi: a.hi}
+	if compareUpper(b.hi, a.hi) > 0 {
+		merged.hi = b.hi
+	}
+	for _, x := range a.excluded {
+		if !b.contains(x) {
+			gap = append(gap, x)
+		}
+	}
+	for _, x := range b.excluded {
+		if !a.contains(x) {
+			gap = append(gap, x)
+		}
+	}
+	merged.exclude(gap)
+	return merged, true
+}
+
+// Simplify returns the minimal equivalent form of e. Overlapping and
+// adjacent groups are merged, groups contained by others and groups which
+// match no version are dropped. The groups of the result are disjoint and
+// sorted in ascending order.
+//
+//	>=1.0.0 <2.0.0 || >=1.5.0 <3.0.0 => >=1.0.0 <3.0.0
+//	<2.0.0 || >2.0.0                 => !=2.0.0
+//
+// If e matches no version at all, an empty RangeExpr is returned.
+func (e RangeExpr) Simplify() RangeExpr {
+	ivs := unionIntervals(e.intervals())
+	out := make(RangeExpr, 0, len(ivs))
+	for _, iv := range ivs {
+		out = append(out, iv.comparators())
+	}
+	return out
+}
+
+// IsSatisfiable checks if at least one version satisfies e. It returns
+// false if the comparators of every group contradict each other, like for
+// ">1.0.0 <1.0.0" or "=1.2.3 =1.2.4", or if e has no groups at all.
+func (e RangeExpr) IsSatisfiable() bool {
+	return len(e.intervals()) > 0
+}
+
+// PackageJSONValue returns e in the most idiomatic form for a dependency of
+// an npm package.json. Groups matching the shape of a caret or tilde range
+// are written as such, e.g. ">=1.2.3 <2.0.0" becomes "^1.2.3". Other groups
+// are written as their comparators.
+func (e RangeExpr) PackageJSONValue() string {
+	groups := make([]string, 0, len(e))
+	for _, group := range e {
+		groups = append(groups, groupPackageJSONValue(group))
+	}
+	return strings.Join(groups, " || ")
+}
+
+func groupPackageJSONValue(group []Comparator) string {
+	iv, ok := groupInterval(group)
+	if !ok || len(iv.excluded) > 0 {
+		return RangeExpr{group}.String()
+	}
+	if !iv.hi.set && (!iv.lo.set || (iv.lo.inclusive && iv.lo.v.isZero())) {
+		// ">=0.0.0" is what "*" is parsed to
+		return "*"
+	}
+	if !iv.lo.set || !iv.hi.set || !iv.lo.inclusive || iv.hi.inclusive || len(iv.hi.v.Pre) > 0 {
+		return RangeExpr{iv.comparators()}.String()
+	}
+
+	lo, hi := iv.lo.v, iv.hi.v
+	if hi.EQ(caretUpper(lo)) {
+		return "^" + lo.String()
+	}
+	if hi.EQ(Version{Major: lo.Major, Minor: lo.Minor + 1}) {
+		return "~" + lo.String()
+	}
+	return RangeExpr{iv.comparators()}.String()
+}
+
+// descriptions are the phrases of Describe, with the version in place of %s.
+var descriptions = map[Operator]string{
+	OpEQ: "exactly %s",
+	OpNE: "not %s",
+	OpGT: "newer than %s",
+	OpGE: "%s or newer",
+	OpLT: "less than %s",
+	OpLE: "at most %s",
+}
+
+// Describe returns e in words for error messages, e.g. "1.2.3 or newer, but
+// less than 2.0.0" for "^1.2.3". The comparators are described as written,
+// groups are joined by "or" and an empty group is described as "any
+// version". Use String for the canonical form.
+func (e RangeExpr) Describe() string {
+	if len(e) == 0 {
+		return "no version"
+	}
+	groups := make([]string, 0, len(e))
+	for _, group := range e {
+		if len(group) == 0 {
+			groups = append(groups, "any version")
+			continue
+		}
+		comps := make([]string, 0, len(group))
+		for _, c := range group {
+			d, ok := descriptions[c.Op]
+			if !ok {
+				// an unknown operator is written as is
+				comps = append(comps, c.String())
+				continue
+			}
+			comps = append(comps, fmt.Sprintf(d, c.Version))
+		}
+		desc := comps[0]
+		if len(comps) > 1 {
+			desc += ", but " + strings.Join(comps[1:], " and ")
+		}
+		groups = append(groups, desc)
+	}
+	return strings.Join(groups, " or ")
+}
+
+// matchGroup checks if v satisfies all comparators of group.
+func matchGroup(group []Comparator, v Version) bool {
+	for _, c := range group {
+		if cmp := c.Op.comparator(); cmp == nil || !cmp(v, c.Version) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchingGroup returns the index of the first group of e which v
+// satisfies. It returns false if v satisfies none of them.
+func (e RangeExpr) MatchingGroup(v Version) (int, bool) {
+	for i, group := range e {
+		if matchGroup(group, v) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// MajorSpan returns how many distinct major versions e can match, e.g.
+// ">=1.0.0 <4.0.0" spans the 3 majors 1, 2 and 3. An upper bound of
+// "<X.0.0" or "<X.0.0-0" does not count major X. It returns false if e has
+// no upper bound.
+func (e RangeExpr) MajorSpan() (uint64, bool) {
+	var span uint64
+	var next uint64 // lowest major not counted yet
+	for _, iv := range unionIntervals(e.intervals()) {
+		if !iv.hi.set {
+			return 0, false
+		}
+		var first, last uint64
+		if iv.lo.set {
+			first = iv.lo.v.Major
+		}
+		last = iv.hi.v.Major
+		// "<X.0.0-0", the lowest prerelease of X.0.0, excludes major X too
+		if hi := iv.hi.v; !iv.hi.inclusive && hi.Minor == 0 && hi.Patch == 0 && (len(hi.Pre) == 0 || hi.EQ(hi.LowestPrerelease())) && last > first {
+			last--
+		}
+		if first < next {
+			first = next
+		}
+		if last >= first {
+			span += last - first + 1
+			next = last + 1
+		}
+	}
+	return span, true
+}
+
+// AllowsBreakingChanges checks if e can match versions which are not
+// compatible to each other by semver rules: versions of more than one major
+// version, or more than a single version of the unstable major version 0,
+// where every release may break. It is meant for advisory warnings, e.g.
+// true for "^0.2.3", ">=1.0.0" and "1.x || 2.x", but false for "^1.2.3".
+func (e RangeExpr) AllowsBreakingChanges() bool {
+	if span, ok := e.MajorSpan(); !ok || span > 1 {
+		return true
+	}
+	for _, iv := range unionIntervals(e.intervals()) {
+		if iv.lo.set && iv.lo.v.Major > 0 {
+			continue
+		}
+		// MajorSpan ensures an upper bound
+		if !iv.lo.set || !iv.lo.v.EQ(iv.hi.v) {
+			return true
+		}
+	}
+	return false
+}
+
+// covers checks if every version of b is a member of iv.
+func (iv interval) covers(b interval) bool {
+	if compareLower(iv.lo, b.lo) > 0 || compareUpper(b.hi, iv.hi) > 0 {
+		return false
+	}
+	for _, x := range iv.excluded {
+		if b.contains(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subset checks if every version satisfying a also satisfies b. A RangeExpr
+// matching no version is a subset of every other.
+//
+//	^1.2.0 is a subset of >=1.0.0 <2.0.0
+func (a RangeExpr) Subset(b RangeExpr) bool {
+	union := unionIntervals(b.intervals())
+	for _, iv := range a.intervals() {
+		covered := false
+		for _, u := range union {
+			if u.covers(iv) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// Equivalent checks if a and b match exactly the same versions, even if
+// they are written differently. It is Subset in both directions.
+//
+//	^1.0.0, >=1.0.0 <2.0.0 and 1.x are equivalent
+func (a RangeExpr) Equivalent(b RangeExpr) bool {
+	return a.Subset(b) && b.Subset(a)
+}
+
+// Bounds returns the lowest and highest version e can match, e.g. 1.2.3
+// inclusive and 2.0.0 exclusive for "^1.2.3". Excluded versions between the
+// bounds are not reported. It returns false if e has no lower or upper
+// bound, matches no version, or its groups do not form a single interval.
+func (e RangeExpr) Bounds() (lower Version, lowerInclusive bool, upper Version, upperInclusive bool, bounded bool) {
+	ivs := unionIntervals(e.intervals())
+	if len(ivs) != 1 || !ivs[0].lo.set || !ivs[0].hi.set {
+		return
+	}
+	iv := ivs[0]
+	return iv.lo.v, iv.lo.inclusive, iv.hi.v, iv.hi.inclusive, true
+}
+
+// ExactVersion returns the only version e matches, like for "=1.2.3" or
+// ">=1.2.3 <=1.2.3". It returns false if e matches more or no versions.
+func (e RangeExpr) ExactVersion() (Version, bool) {
+	ivs := unionIntervals(e.intervals())
+	if len(ivs) != 1 || !ivs[0].lo.set || !ivs[0].hi.set {
+		return Version{}, false
+	}
+	if iv := ivs[0]; iv.lo.v.EQ(iv.hi.v) && iv.lo.inclusive && iv.hi.inclusive {
+		return iv.lo.v, true
+	}
+	return Version{}, false
+}
+
+// intersect returns the RangeExpr matching the versions both a and b match.
+func (a RangeExpr) intersect(b RangeExpr) RangeExpr {
+	out := make(RangeExpr, 0, len(a)*len(b))
+	for _, ga := range a {
+		for _, gb := range b {
+			group := make([]Comparator, 0, len(ga)+len(gb))
+			out = append(out, append(append(group, ga...), gb...))
+		}
+	}
+	return out
+}
+
+// CommonConstraint returns the simplified range matching the versions both
+// ranges a and b match, e.g. ">=1.5.0 <2.0.0" for "^1.2.0" and "^1.5.0". An
+// error is returned if either range can not be parsed, or if they have no
+// version in common.
+func CommonConstraint(a, b string) (string, error) {
+	ea, err := ParseRangeExpr(a)
+	if err != nil {
+		return "", err
+	}
+	eb, err := ParseRangeExpr(b)
+	if err != nil {
+		return "", err
+	}
+	common := ea.intersect(eb).Simplify()
+	if len(common) == 0 {
+		return "", fmt.Errorf("Ranges %q and %q have no version in common", a, b)
+	}
+	return common.String(), nil
+}
+
+// ConstraintBumpType describes how the range newC relates to the range
+// oldC: "equivalent" if both match the same versions, "widen" if newC
+// matches all versions of oldC and more, "narrow" if newC matches only some
+// versions of oldC, and "shift" otherwise. An error is returned if either
+// range can not be parsed.
+func ConstraintBumpType(oldC, newC string) (string, error) {
+	o, err := ParseRangeExpr(oldC)
+	if err != nil {
+		return "", err
+	}
+	n, err := ParseRangeExpr(newC)
+	if err != nil {
+		return "", err
+	}
+	wider, narrower := o.Subset(n), n.Subset(o)
+	switch {
+	case wider && narrower:
+		return "equivalent", nil
+	case wider:
+		return "widen", nil
+	case narrower:
+		return "narrow", nil
+	}
+	return "shift", nil
+}
@@ -0,0 +1,539 @@
+package semver
+
+import (
+	"testing"
+)
+
+// probeVersions are matched against ranges to check that two expressions
+// accept the same versions.
+var probeVersions = []string{
+	"0.0.0", "0.0.1", "0.2.3", "0.3.0", "0.9.0", "1.0.0-rc.1", "1.0.0", "1.2.2",
+	"1.2.3", "1.2.4", "1.4.0", "1.5.0", "1.5.1", "1.6.0", "1.9.9", "2.0.0-beta",
+	"2.0.0", "2.0.1", "2.5.0", "3.0.0", "3.0.1", "4.0.0", "10.0.0",
+}
+
+func TestRangeExprSimplify(t *testing.T) {
+	tests := []struct {
+		i string
+		o string
+	}{
+		{">=1.0.0 <2.0.0 || >=1.5.0 <3.0.0", ">=1.0.0 <3.0.0"},
+		{">=1.5.0 <3.0.0 || >=1.0.0 <2.0.0", ">=1.0.0 <3.0.0"},
+		// contained groups are dropped
+		{">=1.0.0 <3.0.0 || >=1.2.0 <1.5.0", ">=1.0.0 <3.0.0"},
+		{"1.2.3 || >=1.0.0 <2.0.0", ">=1.0.0 <2.0.0"},
+		{"^1.2.3 || ~1.5.0", ">=1.2.3 <2.0.0"},
+		// adjacent groups
+		{">=1.0.0 <2.0.0 || >=2.0.0 <3.0.0", ">=1.0.0 <3.0.0"},
+		{">=1.0.0 <=2.0.0 || >2.0.0 <3.0.0", ">=1.0.0 <3.0.0"},
+		{">=1.0.0 <2.0.0 || >2.0.0 <3.0.0", ">=1.0.0 <3.0.0 !=2.0.0"},
+		{"<2.0.0 || >2.0.0", "!=2.0.0"},
+		{">2.0.0 || <3.0.0", "*"},
+		{"<=1.0.0 || >=1.0.1-0", "*"},
+		{">=1.0.0 <=1.2.3-rc || >=1.2.3-rc.0 <2.0.0", ">=1.0.0 <2.0.0"},
+		{"<=1.0.0 || >=1.0.1", "<=1.0.0 || >=1.0.1"},
+		{"<=1.0.0 || >1.0.1-0", "<=1.0.0 || >1.0.1-0"},
+		// disjoint groups are kept, but sorted
+		{"<1.0.0 || >=2.0.0", "<1.0.0 || >=2.0.0"},
+		{">=3.0.0 || >=1.0.0 <2.0.0", ">=1.0.0 <2.0.0 || >=3.0.0"},
+		{">1.0.0 <2.0.0 || >2.0.0 <3.0.0 || 2.0.0", ">1.0.0 <3.0.0"},
+		// exclusions
+		{">=1.0.0 <2.0.0 !=1.5.0 || >=1.4.0 <1.6.0", ">=1.0.0 <2.0.0"},
+		{">=1.0.0 <2.0.0 !=1.5.0 || >=3.0.0", ">=1.0.0 <2.0.0 !=1.5.0 || >=3.0.0"},
+		{">=1.0.0 <2.0.0 !=1.5.0 || >=1.2.0 <3.0.0 !=1.5.0", ">=1.0.0 <3.0.0 !=1.5.0"},
+		{">=1.0.0 <=2.0.0 !=2.0.0", ">=1.0.0 <2.0.0"},
+		{">=1.0.0 <2.0.0 !=3.0.0 !=1.2.3 !=1.2.3", ">=1.0.0 <2.0.0 !=1.2.3"},
+		{"!=1.2.3 || !=1.2.4", "*"},
+		{"!=1.2.3 || <1.0.0", "!=1.2.3"},
+		// unsatisfiable groups are dropped
+		{">4.0.0 <3.0.0 || 1.2.3", "1.2.3"},
+		{">=1.2.3 <=1.2.3", "1.2.3"},
+		{"1.2.3 1.2.4 || 2.0.0", "2.0.0"},
+		{">1.2.3 <1.2.3", ""},
+		{"1.2.3 !=1.2.3", ""},
+		// caret and tilde ranges in one group intersect
+		{"^1.2.0 ^1.3.0", ">=1.3.0 <2.0.0"},
+		{"~1.2.0 <1.2.9", ">=1.2.0 <1.2.9"},
+		{"^1.2.0 ~1.3.0", ">=1.3.0 <1.4.0"},
+		{"~1.2.0 ~1.2.5 || ^0.2.0 ^0.2.3", ">=0.2.3 <0.3.0 || >=1.2.5 <1.3.0"},
+		{"^1.2.0 ^2.0.0", ""},
+	}
+
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		s := e.Simplify()
+		if o := s.String(); o != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.i, tc.o, o)
+		}
+		r, sr := e.Range(), s.Range()
+		for _, pv := range probeVersions {
+			v := MustParse(pv)
+			if r(v) != sr(v) {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", tc.i, pv, r(v), sr(v))
+			}
+		}
+	}
+}
+
+func TestRangeExprPackageJSONValue(t *testing.T) {
+	tests := []struct {
+		i string
+		o string
+	}{
+		{">=1.2.3 <2.0.0", "^1.2.3"},
+		{"^1.2.3", "^1.2.3"},
+		{"^0.2.3", "^0.2.3"},
+		{">=0.0.3 <0.0.4", "^0.0.3"},
+		{"^1.2.3-rc.1", "^1.2.3-rc.1"},
+		{">=1.2.3 <1.3.0", "~1.2.3"},
+		{"~1.2", "~1.2.0"},
+		{"1.x", "^1.0.0"},
+		{"1.2.3", "1.2.3"},
+		{"*", "*"},
+		{">=1.2.3", ">=1.2.3"},
+		{">=1.2.3 <=2.0.0", ">=1.2.3 <=2.0.0"},
+		{">1.2.3 <2.0.0", ">1.2.3 <2.0.0"},
+		{">=1.2.3 <3.0.0", ">=1.2.3 <3.0.0"},
+		{">=1.2.3 <2.0.0 !=1.5.0", ">=1.2.3 <2.0.0 !=1.5.0"},
+		{"^10.14.1 || ^8.15.0", "^10.14.1 || ^8.15.0"},
+		{"~1.2.3 || >=3.0.0", "~1.2.3 || >=3.0.0"},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+		} else if o := e.PackageJSONValue(); o != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.i, tc.o, o)
+		}
+	}
+}
+
+func TestRangeExprMatchingGroup(t *testing.T) {
+	e, err := ParseRangeExpr("^10.14.1 || ^8.15.0")
+	if err != nil {
+		t.Fatalf("Unexpected error %q", err)
+	}
+	tests := []struct {
+		v  string
+		i  int
+		ok bool
+	}{
+		{"10.14.1", 0, true},
+		{"10.16.1", 0, true},
+		{"8.15.0", 1, true},
+		{"8.95.0", 1, true},
+		{"10.14.0", -1, false},
+		{"9.0.0", -1, false},
+	}
+	for _, tc := range tests {
+		if i, ok := e.MatchingGroup(MustParse(tc.v)); i != tc.i || ok != tc.ok {
+			t.Errorf("Invalid for case %q: Expected %d, %t, got: %d, %t", tc.v, tc.i, tc.ok, i, ok)
+		}
+	}
+
+	// The first of overlapping groups matches
+	e, _ = ParseRangeExpr(">=1.0.0 <3.0.0 || ^2.0.0 || *")
+	if i, ok := e.MatchingGroup(MustParse("2.5.0")); i != 0 || !ok {
+		t.Errorf("Expected first group to match, got: %d, %t", i, ok)
+	}
+	if i, ok := e.MatchingGroup(MustParse("5.0.0")); i != 2 || !ok {
+		t.Errorf("Expected last group to match, got: %d, %t", i, ok)
+	}
+}
+
+func TestRangeExprMajorSpan(t *testing.T) {
+	tests := []struct {
+		i    string
+		span uint64
+		ok   bool
+	}{
+		{">=1 <4", 3, true},
+		{">=1.0.0 <=4.0.0", 4, true},
+		{">=1.2.3 <4.5.0", 4, true},
+		{"^1.2.3", 1, true},
+		{"~1.2.3", 1, true},
+		{"1.2.3", 1, true},
+		{"<2.0.0", 2, true},
+		{"^0.2.3", 1, true},
+		{"1.x || 3.x", 2, true},
+		{"~1.2.0 || ~1.5.0", 1, true},
+		{">=1.0.0 <3.0.0 || ^2.5.0", 2, true},
+		{">=1.0.0 <4.0.0-0", 3, true},
+		{">=1.2.3 <2.0.0-0", 1, true},
+		{">=1.2.3 <=2.0.0-0", 2, true},
+		{">=1.2.3 <2.0.0-alpha", 2, true},
+		{">4 <3", 0, true},
+		{">=1.0.0", 0, false},
+		{"*", 0, false},
+		{"^1.0.0 || >=3.0.0", 0, false},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		if span, ok := e.MajorSpan(); span != tc.span || ok != tc.ok {
+			t.Errorf("Invalid for case %q: Expected %d, %t, got: %d, %t", tc.i, tc.span, tc.ok, span, ok)
+		}
+	}
+}
+
+func TestRangeExprIsSatisfiable(t *testing.T) {
+	tests := []struct {
+		i string
+		b bool
+	}{
+		{">1.0.0 <1.0.0", false},
+		{"=1.2.3 =1.2.4", false},
+		{">4 <3", false},
+		{">=2.0.0 <=1.9.9", false},
+		{"1.2.3 !=1.2.3", false},
+		{">=1.2.3 <=1.2.3 !=1.2.3", false},
+		{">1.0.0 <1.0.0 || 1.2.3 1.2.4", false},
+		{">=1.0.0 <=1.0.0", true},
+		{">1.0.0 <1.0.1", true},
+		{">1.0.0 <1.0.0 || 2.0.0", true},
+		{"^1.2.3 ~1.5.0", true},
+		{"!=1.2.3", true},
+		{"*", true},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		if b := e.IsSatisfiable(); b != tc.b {
+			t.Errorf("Invalid for case %q: Expected %t, got: %t", tc.i, tc.b, b)
+		}
+	}
+	if (RangeExpr{}).IsSatisfiable() {
+		t.Error("Expected empty RangeExpr to be unsatisfiable")
+	}
+	if !(RangeExpr{{}}).IsSatisfiable() {
+		t.Error("Expected empty group to be satisfiable")
+	}
+}
+
+func TestRangeExprAllowsBreakingChanges(t *testing.T) {
+	tests := []struct {
+		i string
+		b bool
+	}{
+		{"^1.2.3", false},
+		{">=1.2.3 <2.0.0-0", false},
+		{">=1.2.3 <2.0.0-beta", true},
+		{"~1.2.3", false},
+		{"1.x", false},
+		{"1.2.3", false},
+		{"0.2.3", false},
+		{">=1.0.0 <2.0.0 !=1.5.0", false},
+		{"1.2.3 || 1.5.x", false},
+		{"^0.2.3", true},
+		{"~0.2.3", true},
+		{"<1.0.0", true},
+		{">=1.0.0", true},
+		{"*", true},
+		{">=1.0.0 <3.0.0", true},
+		{"1.x || 2.x", true},
+		{"1.2.3 || 2.0.0", true},
+		{"0.2.3 || ^1.0.0", true},
+		{">2.0.0 <1.0.0", false},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		if b := e.AllowsBreakingChanges(); b != tc.b {
+			t.Errorf("Invalid for case %q: Expected %t, got: %t", tc.i, tc.b, b)
+		}
+	}
+}
+
+func TestRangeExprSubset(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		subset bool
+	}{
+		{"^1.2.0", ">=1.0.0 <2.0.0", true},
+		{">=1.0.0 <2.0.0", "^1.2.0", false},
+		{"^1.2.0", "^1.2.0", true},
+		{"1.2.3", "^1.2.0", true},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"~1.2.0", "*", true},
+		{"*", "~1.2.0", false},
+		{">=1.0.0", ">=0.5.0", true},
+		{">=0.5.0", ">=1.0.0", false},
+		{"<1.0.0", "<=1.0.0", true},
+		{"<=1.0.0", "<1.0.0", false},
+		{">1.0.0", ">=1.0.0", true},
+		{">=1.0.0", ">1.0.0", false},
+		// across groups
+		{"~1.2.0 || ~1.5.0", "^1.0.0", true},
+		{"^1.0.0", "~1.2.0 || ~1.5.0", false},
+		{">=1.0.0 <2.0.0", ">=1.0.0 <1.5.0 || >=1.5.0 <2.0.0", true},
+		{">=1.0.0 <2.0.0", ">=1.0.0 <1.5.0 || >1.5.0 <2.0.0", false},
+		{"1.x || 3.x", "<2.0.0 || >=3.0.0", true},
+		{"*", "<=1.0.0 || >=1.0.1-0", true},
+		{"*", "<=1.0.0 || >=1.0.1", false},
+		// exclusions
+		{">=1.0.0 <2.0.0 !=1.5.0", ">=1.0.0 <2.0.0", true},
+		{">=1.0.0 <2.0.0", ">=1.0.0 <2.0.0 !=1.5.0", false},
+		{">=1.0.0 <2.0.0 !=1.5.0", ">=1.0.0 <1.5.0 || >1.5.0 <2.0.0", true},
+		{"~1.2.0", "!=1.5.0", true},
+		{"~1.5.0", "!=1.5.0", false},
+		// unsatisfiable
+		{">2.0.0 <1.0.0", "1.2.3", true},
+		{"1.2.3", ">2.0.0 <1.0.0", false},
+	}
+	for _, tc := range tests {
+		a, err := ParseRangeExpr(tc.a)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.a, err)
+			continue
+		}
+		b, err := ParseRangeExpr(tc.b)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.b, err)
+			continue
+		}
+		if subset := a.Subset(b); subset != tc.subset {
+			t.Errorf("Invalid for case %q subset of %q: Expected %t, got: %t", tc.a, tc.b, tc.subset, subset)
+		}
+	}
+}
+
+func TestRangeExprEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		equivalent bool
+	}{
+		{"^1.0.0", ">=1.0.0 <2.0.0", true},
+		{"^1.0.0", "1.x", true},
+		{">=1.0.0 <2.0.0", "1.x", true},
+		{"~1.2.0", "1.2.x", true},
+		{"*", ">=0.0.0", true},
+		{"1.2.3", "=v1.2.3", true},
+		{">=1.0.0 <1.5.0 || >=1.5.0 <2.0.0", "^1.0.0", true},
+		{"<2.0.0 || >=3.0.0", "!=2.x", true},
+		{">=1.0.0 <2.0.0 !=1.5.0", ">=1.0.0 <1.5.0 || >1.5.0 <2.0.0", true},
+		{">2.0.0 <1.0.0", ">=3.0.0 <=2.0.0", true},
+		{">=0.0.0 <=1.0.0 || >=1.0.1-0", "*", true},
+		{"<=1.2.3-rc || >=1.2.3-rc.0", "<2.0.0 || >=1.0.0", true},
+		{">=0.0.0 <=1.0.0 || >=1.0.1", "*", false},
+		{"^1.2.0", ">=1.0.0 <2.0.0", false},
+		{"^1.0.0", "<=2.0.0 >=1.0.0", false},
+		{">1.0.0", ">=1.0.0", false},
+		{"1.2.3", "1.2.4", false},
+	}
+	for _, tc := range tests {
+		a, err := ParseRangeExpr(tc.a)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.a, err)
+			continue
+		}
+		b, err := ParseRangeExpr(tc.b)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.b, err)
+			continue
+		}
+		if eq := a.Equivalent(b); eq != tc.equivalent {
+			t.Errorf("Invalid for case %q equivalent to %q: Expected %t, got: %t", tc.a, tc.b, tc.equivalent, eq)
+		}
+		if eq := b.Equivalent(a); eq != tc.equivalent {
+			t.Errorf("Invalid for case %q equivalent to %q: Expected %t, got: %t", tc.b, tc.a, tc.equivalent, eq)
+		}
+	}
+}
+
+func TestRangeExprBounds(t *testing.T) {
+	tests := []struct {
+		i              string
+		lower          string
+		lowerInclusive bool
+		upper          string
+		upperInclusive bool
+		bounded        bool
+	}{
+		{"^1.2.3", "1.2.3", true, "2.0.0", false, true},
+		{"~1.2.3", "1.2.3", true, "1.3.0", false, true},
+		{">1.0.0 <=1.5.0", "1.0.0", false, "1.5.0", true, true},
+		{"1.2.3", "1.2.3", true, "1.2.3", true, true},
+		{">=1.0.0 <2.0.0 !=1.5.0", "1.0.0", true, "2.0.0", false, true},
+		{">=1.0.0 <2.0.0 || >=1.5.0 <3.0.0", "1.0.0", true, "3.0.0", false, true},
+		{">=1.0.0 <2.0.0 || >2.0.0 <3.0.0", "1.0.0", true, "3.0.0", false, true},
+		{"1.x || 3.x", "", false, "", false, false},
+		{">=1.0.0", "", false, "", false, false},
+		{"<2.0.0", "", false, "", false, false},
+		{"*", "", false, "", false, false},
+		{">4 <3", "", false, "", false, false},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		lower, lowerInclusive, upper, upperInclusive, bounded := e.Bounds()
+		if bounded != tc.bounded {
+			t.Errorf("Invalid for case %q: Expected bounded %t, got: %t", tc.i, tc.bounded, bounded)
+			continue
+		}
+		if !bounded {
+			continue
+		}
+		if lower.String() != tc.lower || lowerInclusive != tc.lowerInclusive || upper.String() != tc.upper || upperInclusive != tc.upperInclusive {
+			t.Errorf("Invalid for case %q: Expected %q %t, %q %t, got: %q %t, %q %t", tc.i,
+				tc.lower, tc.lowerInclusive, tc.upper, tc.upperInclusive, lower, lowerInclusive, upper, upperInclusive)
+		}
+	}
+}
+
+func TestRangeExprExactVersion(t *testing.T) {
+	tests := []struct {
+		i  string
+		v  string
+		ok bool
+	}{
+		{"=1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3-rc.1", "1.2.3-rc.1", true},
+		{">=1.2.3 <=1.2.3", "1.2.3", true},
+		{"1.2.3 || =1.2.3", "1.2.3", true},
+		{"1.2.3 || >4 <3", "1.2.3", true},
+		{"^1.2.3", "", false},
+		{"1.2.3 || 1.2.4", "", false},
+		{">=1.2.3 <1.2.3", "", false},
+		{"1.2.3 !=1.2.3", "", false},
+		{">=1.2.3", "", false},
+		{"*", "", false},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		if v, ok := e.ExactVersion(); ok != tc.ok || (ok && v.String() != tc.v) {
+			t.Errorf("Invalid for case %q: Expected %q, %t, got: %q, %t", tc.i, tc.v, tc.ok, v, ok)
+		}
+	}
+}
+
+func TestCommonConstraint(t *testing.T) {
+	tests := []struct {
+		a, b string
+		o    string
+	}{
+		{"^1.2.0", "^1.5.0", ">=1.5.0 <2.0.0"},
+		{"^1.2.0", "~1.4.2", ">=1.4.2 <1.5.0"},
+		{"^1.2.0", ">=1.0.0", ">=1.2.0 <2.0.0"},
+		{">=1.0.0 !=1.5.0", "<2.0.0", ">=1.0.0 <2.0.0 !=1.5.0"},
+		{"^1.0.0 || ^3.0.0", ">=1.5.0 <3.5.0", ">=1.5.0 <2.0.0 || >=3.0.0 <3.5.0"},
+		{"^1.2.0", "1.4.0", "1.4.0"},
+		{"*", "*", ">=0.0.0"},
+		// no common version
+		{"^1.2.0", "^2.0.0", ""},
+		{"<1.0.0", ">=1.0.0", ""},
+		{"1.2.3", "!=1.2.3", ""},
+		// invalid ranges
+		{"^1.2.0", "foo", ""},
+		{"", "^1.2.0", ""},
+	}
+	for _, tc := range tests {
+		o, err := CommonConstraint(tc.a, tc.b)
+		if tc.o == "" {
+			if err == nil {
+				t.Errorf("Expected error for %q and %q, got %q", tc.a, tc.b, o)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error for %q and %q: %s", tc.a, tc.b, err)
+			continue
+		}
+		if o != tc.o {
+			t.Errorf("Invalid for case %q and %q: Expected %q, got: %q", tc.a, tc.b, tc.o, o)
+		}
+		a, b, r := MustParseRange(tc.a), MustParseRange(tc.b), MustParseRange(o)
+		for _, pv := range probeVersions {
+			if v := MustParse(pv); r(v) != (a(v) && b(v)) {
+				t.Errorf("Invalid for case %q and %q matching %q: Expected %t, got: %t", tc.a, tc.b, pv, a(v) && b(v), r(v))
+			}
+		}
+	}
+}
+
+func TestConstraintBumpType(t *testing.T) {
+	tests := []struct {
+		oldC, newC string
+		o          string
+	}{
+		{"^1.2.0", "^1.0.0", "widen"},
+		{"^1.2.0", "^1.2.0 || ^2.0.0", "widen"},
+		{"^1.2.0", "~1.2.0", "narrow"},
+		{"^1.2.0", "1.4.0", "narrow"},
+		{"^1.2.0", ">=1.2.0 <2.0.0", "equivalent"},
+		{"~1.2", "1.2.x", "equivalent"},
+		{"^1.2.0", "^2.0.0", "shift"},
+		{"^1.2.0", ">=1.5.0 <2.5.0", "shift"},
+		// errors
+		{"^1.2.0", "foo", ""},
+		{"", "^1.2.0", ""},
+	}
+	for _, tc := range tests {
+		o, err := ConstraintBumpType(tc.oldC, tc.newC)
+		if tc.o == "" {
+			if err == nil {
+				t.Errorf("Expected error for %q to %q, got %q", tc.oldC, tc.newC, o)
+			}
+		} else if err != nil {
+			t.Errorf("Error for %q to %q: %s", tc.oldC, tc.newC, err)
+		} else if o != tc.o {
+			t.Errorf("Invalid for case %q to %q: Expected %q, got: %q", tc.oldC, tc.newC, tc.o, o)
+		}
+	}
+}
+
+func TestRangeExprDescribe(t *testing.T) {
+	tests := []struct {
+		i string
+		o string
+	}{
+		{"^1.2.3", "1.2.3 or newer, but less than 2.0.0"},
+		{">=1 <2", "1.0.0 or newer, but less than 2.0.0"},
+		{"1.2.3", "exactly 1.2.3"},
+		{"=v1.2.3-rc.1+build", "exactly 1.2.3-rc.1+build"},
+		{">1.0.0 <=1.5.0 !=1.2.0", "newer than 1.0.0, but at most 1.5.0 and not 1.2.0"},
+		{"1.2.3 || >=2.0.0", "exactly 1.2.3 or 2.0.0 or newer"},
+		{"*", "0.0.0 or newer"},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		if o := e.Describe(); o != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.i, tc.o, o)
+		}
+	}
+	if o := (RangeExpr{{}}).Describe(); o != "any version" {
+		t.Errorf("Invalid for empty group: Expected %q, got: %q", "any version", o)
+	}
+	if o := (RangeExpr{}).Describe(); o != "no version" {
+		t.Errorf("Invalid for empty RangeExpr: Expected %q, got: %q", "no version", o)
+	}
+	unknown := RangeExpr{{{Op: OpGE, Version: MustParse("1.0.0")}, {Op: "~", Version: MustParse("1.2.3")}}}
+	if o, want := unknown.Describe(), "1.0.0 or newer, but ~1.2.3"; o != want {
+		t.Errorf("Invalid for unknown operator: Expected %q, got: %q", want, o)
+	}
+}
@@ -12,6 +12,17 @@ import (
 // in the Node ecosystem, so I've tried to keep it as close to the
 // original source as I reasonably can
 
+var (
+	// rangeRegex holds the compiled expressions of getRegex. Compiling them
+	// is by far the most expensive part of parsing a range, so it is done
+	// once only.
+	rangeRegex = getRegex()
+	spaceRegex = regexp.MustCompile("\\s+")
+	// equalRegex matches a standalone "==" operator, but not the "=" of
+	// another operator like ">=" or "!=" followed by one.
+	equalRegex = regexp.MustCompile(`(^|\s)==([^=<>!~^]|$)`)
+)
+
 func getRegex() map[string]*regexp.Regexp {
 	// Max safe segment length for coercion.
 	var MaxSafeComponentLength = 16
@@ -188,11 +199,15 @@ func getRegex() map[string]*regexp.Regexp {
 func parseRange(s string) []string {
 	var out []string
 	s = strings.TrimSpace(s)
-	re := getRegex()
+	re := rangeRegex
 
 	// `1.2.3 - 1.2.4` => `>=1.2.3 <=1.2.4`
 	s = hyphenReplace(re, s)
 
+	// `==1.2.x` => `=1.2.x`, so wildcards behind an explicit
+	// equality operator are expanded like bare ones
+	s = equalRegex.ReplaceAllString(s, "$1=$2")
+
 	// `> 1.2.3 < 1.2.5` => `>1.2.3 <1.2.5`
 	s = re["COMPARATORTRIM"].ReplaceAllString(s, "$1$2$3")
 	// `~ 1.2.3` => `~1.2.3`
@@ -200,7 +215,7 @@ func parseRange(s string) []string {
 	// `^ 1.2.3` => `^1.2.3
 	s = re["CARETTRIM"].ReplaceAllString(s, "$1^")
 	// normalize spaces
-	s = strings.Join(regexp.MustCompile("\\s+").Split(s, -1), " ")
+	s = strings.Join(spaceRegex.Split(s, -1), " ")
 
 	// At this point, the range is completely trimmed and
 	// ready to be split into comparators.
@@ -210,7 +225,7 @@ func parseRange(s string) []string {
 	}
 
 	// join and split by spaces once more
-	return regexp.MustCompile("\\s+").Split(strings.Join(out, " "), -1)
+	return spaceRegex.Split(strings.Join(out, " "), -1)
 }
 
 // comprised of xranges, tildes, stars, and gtlt's at this point.
@@ -228,6 +243,9 @@ func parseComparatorString(re map[string]*regexp.Regexp, s string) string {
 // 1.2 - 3.4.5 => >=1.2.0 <=3.4.5
 // 1.2.3 - 3.4 => >=1.2.3 <3.5.0 Any 3.4.x will do
 // 1.2 - 3.4 => >=1.2.0 <3.5.0
+// 1.2.3-rc.1 - 2.0.0-rc.1 => >=1.2.3-rc.1 <=2.0.0-rc.1
+// Only a hyphen surrounded by whitespace separates the versions, so the
+// hyphens of prereleases are left intact.
 func hyphenReplace(re map[string]*regexp.Regexp, s string) string {
 	// if we don't match for a hyphen range, return the string unchanged
 	if !re["HYPHENRANGE"].MatchString(s) {
@@ -262,11 +280,9 @@ func hyphenReplace(re map[string]*regexp.Regexp, s string) string {
 	if isX(tM) {
 		to = ""
 	} else if isX(tm) {
-		major, _ := strconv.Atoi(tM)
-		to = "<" + strconv.Itoa(major+1) + ".0.0"
+		to = "<" + increment(tM) + ".0.0"
 	} else if isX(tp) {
-		minor, _ := strconv.Atoi(tm)
-		to = "<" + tM + "." + strconv.Itoa(minor+1) + ".0"
+		to = "<" + tM + "." + increment(tm) + ".0"
 	} else if len(tpr) > 0 {
 		to = "<=" + tM + "." + tm + "." + tp + "-" + tpr
 	} else {
@@ -281,10 +297,11 @@ func hyphenReplace(re map[string]*regexp.Regexp, s string) string {
 // ~1.2, ~1.2.x, ~>1.2, ~>1.2.x --> >=1.2.0 <1.3.0
 // ~1.2.3, ~>1.2.3 --> >=1.2.3 <1.3.0
 // ~1.2.0, ~>1.2.0 --> >=1.2.0 <1.3.0
+// ~1.2.3-rc.1, ~>1.2.3-rc.1 --> >=1.2.3-rc.1 <1.3.0
 func replaceTildes(re map[string]*regexp.Regexp, s string) string {
 	var acc []string
 	s = strings.TrimSpace(s)
-	parts := regexp.MustCompile("\\s+").Split(s, -1)
+	parts := spaceRegex.Split(s, -1)
 	for _, p := range parts {
 		acc = append(acc, replaceTilde(re, p))
 	}
@@ -304,25 +321,20 @@ func replaceTilde(re map[string]*regexp.Regexp, s string) string {
 	p := match[3]
 	pr := match[4]
 
-	// parsed version numbers as ints
-	// the regex ensures they are valid numbers
-	major, _ := strconv.Atoi(M)
-	minor, _ := strconv.Atoi(m)
-
 	if isX(M) {
 		ret = ""
 	} else if isX(m) {
-		ret = ">=" + M + ".0.0 <" + strconv.Itoa(major+1) + ".0.0"
+		ret = ">=" + M + ".0.0 <" + increment(M) + ".0.0"
 	} else if isX(p) {
 		// ~1.2 == >=1.2.0 <1.3.0
-		ret = ">=" + M + "." + m + ".0 <" + M + "." + strconv.Itoa(minor+1) + ".0"
+		ret = ">=" + M + "." + m + ".0 <" + M + "." + increment(m) + ".0"
 	} else if len(pr) > 0 {
 		ret = ">=" + M + "." + m + "." + p + "-" + pr +
-			" <" + M + "." + strconv.Itoa(minor+1) + ".0"
+			" <" + M + "." + increment(m) + ".0"
 	} else {
 		// ~1.2.3 == >=1.2.3 <1.3.0
 		ret = ">=" + M + "." + m + "." + p +
-			" <" + M + "." + strconv.Itoa(minor+1) + ".0"
+			" <" + M + "." + increment(m) + ".0"
 	}
 
 	return ret
@@ -336,7 +348,7 @@ func replaceTilde(re map[string]*regexp.Regexp, s string) string {
 func replaceCarets(re map[string]*regexp.Regexp, s string) string {
 	var acc []string
 	s = strings.TrimSpace(s)
-	parts := regexp.MustCompile("\\s+").Split(s, -1)
+	parts := spaceRegex.Split(s, -1)
 	for _, p := range parts {
 		acc = append(acc, replaceCaret(re, p))
 	}
@@ -356,47 +368,41 @@ func replaceCaret(re map[string]*regexp.Regexp, s string) string {
 	p := match[3]
 	pr := match[4]
 
-	// parsed version numbers as ints
-	// the regex ensures they are valid numbers
-	major, _ := strconv.Atoi(M)
-	minor, _ := strconv.Atoi(m)
-	patch, _ := strconv.Atoi(p)
-
 	if isX(M) {
 		ret = ""
 	} else if isX(m) {
-		ret = ">=" + M + ".0.0 <" + strconv.Itoa(major+1) + ".0.0"
+		ret = ">=" + M + ".0.0 <" + increment(M) + ".0.0"
 	} else if isX(p) {
 		if M == "0" {
-			ret = ">=" + M + "." + m + ".0 <" + M + "." + strconv.Itoa(minor+1) + ".0"
+			ret = ">=" + M + "." + m + ".0 <" + M + "." + increment(m) + ".0"
 		} else {
-			ret = ">=" + M + "." + m + ".0 <" + strconv.Itoa(major+1) + ".0.0"
+			ret = ">=" + M + "." + m + ".0 <" + increment(M) + ".0.0"
 		}
 	} else if len(pr) > 0 {
 		if M == "0" {
 			if m == "0" {
 				ret = ">=" + M + "." + m + "." + p + "-" + pr +
-					" <" + M + "." + m + "." + strconv.Itoa(patch+1)
+					" <" + M + "." + m + "." + increment(p)
 			} else {
 				ret = ">=" + M + "." + m + "." + p + "-" + pr +
-					" <" + M + "." + strconv.Itoa(minor+1) + ".0"
+					" <" + M + "." + increment(m) + ".0"
 			}
 		} else {
 			ret = ">=" + M + "." + m + "." + p + "-" + pr +
-				" <" + strconv.Itoa(major+1) + ".0.0"
+				" <" + increment(M) + ".0.0"
 		}
 	} else {
 		if M == "0" {
 			if m == "0" {
 				ret = ">=" + M + "." + m + "." + p +
-					" <" + M + "." + m + "." + strconv.Itoa(patch+1)
+					" <" + M + "." + m + "." + increment(p)
 			} else {
 				ret = ">=" + M + "." + m + "." + p +
-					" <" + M + "." + strconv.Itoa(minor+1) + ".0"
+					" <" + M + "." + increment(m) + ".0"
 			}
 		} else {
 			ret = ">=" + M + "." + m + "." + p +
-				" <" + strconv.Itoa(major+1) + ".0.0"
+				" <" + increment(M) + ".0.0"
 		}
 	}
 
@@ -406,7 +412,7 @@ func replaceCaret(re map[string]*regexp.Regexp, s string) string {
 func replaceXRanges(re map[string]*regexp.Regexp, s string) string {
 	var acc []string
 	s = strings.TrimSpace(s)
-	parts := regexp.MustCompile("\\s+").Split(s, -1)
+	parts := spaceRegex.Split(s, -1)
 	for _, p := range parts {
 		acc = append(acc, replaceXRange(re, p))
 	}
@@ -435,19 +441,14 @@ func replaceXRange(re map[string]*regexp.Regexp, s string) string {
 		gtlt = ""
 	}
 
-	// parsed version numbers as ints
-	// the regex ensures they are valid numbers
-	major, _ := strconv.Atoi(M)
-	minor, _ := strconv.Atoi(m)
-
 	if xM {
-		if gtlt == ">" || gtlt == "<" {
-			// nothing is allowed
-			ret = "<0.0.0"
-		} else {
-			// nothing is forbidden
-			ret = "*"
+		if gtlt == ">" || gtlt == "<" || !isX(m) || !isX(p) {
+			// `>x`, `<x` and `x.2.3` have no sensible meaning, leave them
+			// to be rejected by splitComparatorVersion
+			return s
 		}
+		// nothing is forbidden
+		ret = "*"
 	} else if len(gtlt) > 0 && anyX {
 		// we know patch is an x, because we have any x at all.
 		// replace X with 0
@@ -462,11 +463,11 @@ func replaceXRange(re map[string]*regexp.Regexp, s string) string {
 			// >1.2.3 => >= 1.2.4
 			gtlt = ">="
 			if xm {
-				M = strconv.Itoa(major + 1)
+				M = increment(M)
 				m = "0"
 				p = "0"
 			} else {
-				m = strconv.Itoa(minor + 1)
+				m = increment(m)
 				p = "0"
 			}
 		} else if gtlt == "<=" {
@@ -474,30 +475,52 @@ func replaceXRange(re map[string]*regexp.Regexp, s string) string {
 			// pass.  Similarly, <=7.x is actually <8.0.0, etc.
 			gtlt = "<"
 			if xm {
-				M = strconv.Itoa(major + 1)
+				M = increment(M)
 			} else {
-				m = strconv.Itoa(minor + 1)
+				m = increment(m)
 			}
 		}
 
 		ret = gtlt + M + "." + m + "." + p
 	} else if xm {
-		ret = ">=" + M + ".0.0 <" + strconv.Itoa(major+1) + ".0.0"
+		ret = ">=" + M + ".0.0 <" + increment(M) + ".0.0"
 	} else if xp {
-		ret = ">=" + M + "." + m + ".0 <" + M + "." + strconv.Itoa(minor+1) + ".0"
+		ret = ">=" + M + "." + m + ".0 <" + M + "." + increment(m) + ".0"
 	}
 
 	return ret
 }
 
+// A bare > or < in front of the star is left alone, so ">*" and "<*" are
+// rejected like ">x" instead of matching every version.
 func replaceStars(re map[string]*regexp.Regexp, s string) string {
-	return re["STAR"].ReplaceAllString(strings.TrimSpace(s), ">=0.0.0")
+	return re["STAR"].ReplaceAllStringFunc(strings.TrimSpace(s), func(m string) string {
+		if (m[0] == '>' || m[0] == '<') && (len(m) < 2 || m[1] != '=') {
+			return m
+		}
+		return ">=0.0.0"
+	})
 }
 
 func replaceV(re map[string]*regexp.Regexp, s string) string {
 	return s
 }
 
+// increment adds one to the decimal number s. It operates on the digits
+// directly, so numbers too large for an int are carried correctly and
+// rejected by Parse later on instead of silently wrapping.
+func increment(s string) string {
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '9' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b)
+}
+
 func isX(s string) bool {
 	return len(s) == 0 || s == "x" || s == "X" || s == "*"
 }
@@ -15,6 +15,10 @@ func TestHyphenReplace(t *testing.T) {
 		{"1.2 - 3.4.5", ">=1.2.0 <=3.4.5"},
 		{"1.2.3 - 3.4", ">=1.2.3 <3.5.0"},
 		{"1.2 - 3.4", ">=1.2.0 <3.5.0"},
+		{"1.2.3-rc.1 - 2.0.0", ">=1.2.3-rc.1 <=2.0.0"},
+		{"1.2.3-rc.1 - 2.0.0-rc.1", ">=1.2.3-rc.1 <=2.0.0-rc.1"},
+		{"1.2.3-a-b - 2.0.0-c-d", ">=1.2.3-a-b <=2.0.0-c-d"},
+		{"1.2.3-rc.1-2.0.0", "1.2.3-rc.1-2.0.0"},
 	}
 
 	for _, tc := range tests {
@@ -44,6 +48,9 @@ func TestTildeReplace(t *testing.T) {
 		{"~>1.2.3", ">=1.2.3 <1.3.0"},
 		{"~1.2.0", ">=1.2.0 <1.3.0"},
 		{"~>1.2.0", ">=1.2.0 <1.3.0"},
+		{"~1.2.3-rc.1", ">=1.2.3-rc.1 <1.3.0"},
+		{"~>1.2.3-rc.1", ">=1.2.3-rc.1 <1.3.0"},
+		{"~0.0.1-beta.2", ">=0.0.1-beta.2 <0.1.0"},
 	}
 
 	for _, tc := range tests {
@@ -78,3 +85,35 @@ func TestCaretReplace(t *testing.T) {
 		}
 	}
 }
+
+func TestIncrement(t *testing.T) {
+	tests := []struct {
+		i string
+		o string
+	}{
+		{"0", "1"},
+		{"8", "9"},
+		{"9", "10"},
+		{"199", "200"},
+		{"18446744073709551615", "18446744073709551616"},
+	}
+
+	for _, tc := range tests {
+		if o := increment(tc.i); o != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.i, tc.o, o)
+		}
+	}
+}
+
+func TestParseRangeOverflow(t *testing.T) {
+	for _, s := range []string{
+		"^18446744073709551615.x",
+		"~1.18446744073709551615",
+		">1.99999999999999999999.x",
+		"1.2 - 18446744073709551615",
+	} {
+		if _, err := ParseRange(s); err == nil {
+			t.Errorf("Expected error parsing range %q, got none", s)
+		}
+	}
+}
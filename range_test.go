@@ -1,6 +1,7 @@
 package semver
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -148,6 +149,75 @@ func TestBuildVersionRange(t *testing.T) {
 
 }
 
+func TestParseRangeEmpty(t *testing.T) {
+	for _, s := range []string{"", " ", "\t\n  "} {
+		if _, err := ParseRange(s); err != ErrEmptyRange {
+			t.Errorf("Expected ErrEmptyRange parsing range %q, got: %v", s, err)
+		}
+	}
+	for _, s := range []string{"*", " * ", "x"} {
+		r, err := ParseRange(s)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", s, err)
+		} else if !r(MustParse("1.2.3")) {
+			t.Errorf("Expected range %q to match any version", s)
+		}
+	}
+	if _, err := ParseRange("foo"); err == ErrEmptyRange {
+		t.Errorf("Expected invalid range not to be reported as empty")
+	}
+}
+
+func TestParseRangeEmptyOROperand(t *testing.T) {
+	tests := []string{
+		">1.0.0 ||",
+		">1.0.0 || ",
+		"|| >1.0.0",
+		">1.0.0 || || >2.0.0",
+		">1.0.0 |||| >2.0.0",
+		"||",
+		" || ",
+	}
+	for _, s := range tests {
+		_, err := ParseRange(s)
+		if err == nil {
+			t.Errorf("Expected error for range %q, got none", s)
+			continue
+		}
+		want := fmt.Sprintf("Could not parse Range %q: empty operand of \"||\", use \"*\" to match any version", strings.TrimSpace(s))
+		if err.Error() != want {
+			t.Errorf("Invalid for case %q: Expected error %q, got: %q", s, want, err)
+		}
+	}
+
+	// an explicit wildcard operand is fine
+	r, err := ParseRange(">1.0.0 || *")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !r(MustParse("0.1.0")) {
+		t.Errorf("Expected %q to match %q", ">1.0.0 || *", "0.1.0")
+	}
+}
+
+func TestParseRangeReversedOperators(t *testing.T) {
+	tests := []struct {
+		i   string
+		err string
+	}{
+		{"=<1.2.3", `Could not parse Range "=<1.2.3": Could not parse comparator "=<" in "=<1.2.3", did you mean "<="?`},
+		{"=>1.2.3", `Could not parse Range "=>1.2.3": Could not parse comparator "=>" in "=>1.2.3", did you mean ">="?`},
+		{">=1.0.0 =<2.0.0", `Could not parse Range "=<2.0.0": Could not parse comparator "=<" in "=<2.0.0", did you mean "<="?`},
+	}
+	for _, tc := range tests {
+		if _, err := ParseRange(tc.i); err == nil {
+			t.Errorf("Expected error parsing range %q, got none", tc.i)
+		} else if err.Error() != tc.err {
+			t.Errorf("Invalid error for case %q: Expected %q, got: %q", tc.i, tc.err, err)
+		}
+	}
+}
+
 func TestVersionRangeToRange(t *testing.T) {
 	vr := versionRange{
 		v: MustParse("1.2.3"),
@@ -206,6 +276,69 @@ func TestRangeOR(t *testing.T) {
 	}
 }
 
+func TestRangeMatchString(t *testing.T) {
+	r := MustParseRange(">=1.0.0 <2.0.0")
+	tests := []struct {
+		s   string
+		b   bool
+		err bool
+	}{
+		{"1.2.3", true, false},
+		{"v1.2.3", true, false},
+		{"2.0.0", false, false},
+		{"1.9.9-rc.1+build", true, false},
+		{"1.2", false, true},
+		{"", false, true},
+		{"1.2.3-a..b", false, true},
+	}
+	for _, tc := range tests {
+		b, err := r.MatchString(tc.s)
+		if (err != nil) != tc.err {
+			t.Errorf("Invalid for case %q: Expected error %t, got: %v", tc.s, tc.err, err)
+		}
+		if b != tc.b {
+			t.Errorf("Invalid for case %q: Expected %t, got: %t", tc.s, tc.b, b)
+		}
+	}
+	if _, err := r.MatchString("1.2.x"); err == nil || err.Error() != `Invalid character(s) found in patch number "x"` {
+		t.Errorf("Expected the Parse error, got %v", err)
+	}
+}
+
+func TestRangeExprRangeSingleComparator(t *testing.T) {
+	ops := []Operator{OpEQ, OpNE, OpGT, OpGE, OpLT, OpLE}
+	vs := []string{"1.2.2", "1.2.3-rc.1", "1.2.3", "1.2.3+build", "1.2.4"}
+	for _, op := range ops {
+		c := Comparator{Op: op, Version: MustParse("1.2.3")}
+		r := RangeExpr{{c}}.Range()
+		for _, s := range vs {
+			v := MustParse(s)
+			if res, want := r(v), matchGroup([]Comparator{c}, v); res != want {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", c, s, want, res)
+			}
+		}
+	}
+}
+
+func TestRangeExprRangeUnknownOperator(t *testing.T) {
+	unknown := Comparator{Op: "~", Version: MustParse("1.2.3")}
+	exprs := []RangeExpr{
+		{{unknown}},
+		{{{Op: OpGE, Version: MustParse("1.0.0")}, unknown}},
+		{{unknown}, {{Op: OpEQ, Version: MustParse("2.0.0")}}},
+	}
+	for _, e := range exprs {
+		r := e.Range()
+		for _, s := range []string{"1.0.0", "1.2.3", "2.0.0"} {
+			v := MustParse(s)
+			_, want := e.MatchingGroup(v)
+			if res := r(v); res != want {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", e, s, want, res)
+			}
+		}
+	}
+}
+
 func TestParseRange(t *testing.T) {
 	type tv struct {
 		v string
@@ -315,6 +448,36 @@ func TestParseRange(t *testing.T) {
 			{"1.2.6", false},
 			{"1.3.0", true},
 		}},
+		// Wildcards behind an explicit equality operator
+		{"=1.x", []tv{
+			{"0.9.9", false},
+			{"1.0.0", true},
+			{"1.9.9", true},
+			{"2.0.0", false},
+		}},
+		{"=1.*", []tv{
+			{"0.9.9", false},
+			{"1.0.0", true},
+			{"1.9.9", true},
+			{"2.0.0", false},
+		}},
+		{"==2.0.x", []tv{
+			{"1.9.9", false},
+			{"2.0.0", true},
+			{"2.0.9", true},
+			{"2.1.0", false},
+		}},
+		{"== 1.2.x", []tv{
+			{"1.1.9", false},
+			{"1.2.0", true},
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
+		{"==1.2", []tv{
+			{"1.2.0", true},
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
 		// Combined Expressions
 		{">1.2.2 <1.2.4 || >=2.0.0", []tv{
 			{"1.2.2", false},
@@ -400,6 +563,20 @@ func TestParseRange(t *testing.T) {
 			{"10.1.0", true},
 			{"10.2.0", false},
 		}},
+		// The prerelease is kept in the lower bound only
+		{"~1.2.3-rc.1", []tv{
+			{"1.2.3-beta", false},
+			{"1.2.3-rc.1", true},
+			{"1.2.3-rc.2", true},
+			{"1.2.3", true},
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
+		{"~>1.2.3-rc.1+build", []tv{
+			{"1.2.3-rc.0", false},
+			{"1.2.3-rc.2", true},
+			{"1.3.0", false},
+		}},
 		// Should act just like 10.x
 		{"~10.x", []tv{
 			{"10.1.4", true},
@@ -417,6 +594,29 @@ func TestParseRange(t *testing.T) {
 			{"10.99.99", false},
 			{"10.0.0", false},
 		}},
+		// multiple caret and tilde ranges are linked by logical AND
+		{"^1.2.0 ^1.3.0", []tv{
+			{"1.2.5", false},
+			{"1.3.0", true},
+			{"1.9.9", true},
+			{"2.0.0", false},
+		}},
+		{"~1.2.0 <1.2.9", []tv{
+			{"1.1.9", false},
+			{"1.2.0", true},
+			{"1.2.8", true},
+			{"1.2.9", false},
+			{"1.3.0", false},
+		}},
+		{"^1.2.0 ~1.3.0", []tv{
+			{"1.2.5", false},
+			{"1.3.4", true},
+			{"1.4.0", false},
+		}},
+		{"^1.2.0 ^2.0.0", []tv{
+			{"1.9.9", false},
+			{"2.0.0", false},
+		}},
 		{"^10.1.2", []tv{
 			{"10.1.4", true},
 			{"10.1.1", false},
@@ -457,6 +657,65 @@ func TestParseRange(t *testing.T) {
 		}},
 		// what would this even mean?
 		{"^x", nil},
+		// a wildcard major matches any version with >=, <= or =
+		{">=x", []tv{
+			{"0.0.0", true},
+			{"1021.99.99", true},
+		}},
+		{"<=x.x", []tv{
+			{"0.0.0", true},
+			{"1021.99.99", true},
+		}},
+		{"=X.x.x", []tv{
+			{"0.0.0", true},
+			{"1021.99.99", true},
+		}},
+		{">=1.0.0 <=x", []tv{
+			{"0.9.0", false},
+			{"1.0.0", true},
+			{"1021.99.99", true},
+		}},
+		// negated X-Ranges match everything outside of them
+		{"!=1.x", []tv{
+			{"0.9.0", true},
+			{"1.0.0", false},
+			{"1.5.0", false},
+			{"2.0.0", true},
+			{"2.0.0-beta", false},
+		}},
+		{"!1.2", []tv{
+			{"1.1.9", true},
+			{"1.2.0", false},
+			{"1.2.9", false},
+			{"1.3.0", true},
+		}},
+		{">=0.5.0 <3.0.0 !=1.x", []tv{
+			{"0.4.0", false},
+			{"0.9.0", true},
+			{"1.5.0", false},
+			{"2.5.0", true},
+			{"3.0.0", false},
+		}},
+		{"!=1.x !=3.x || 3.5.0", []tv{
+			{"1.5.0", false},
+			{"2.5.0", true},
+			{"3.1.0", false},
+			{"3.5.0", true},
+			{"4.0.0", true},
+		}},
+		{"!=x", nil},
+		{"!=*", nil},
+		// but is invalid for other operators or with fixed numbers after it
+		{">x", nil},
+		{"<x", nil},
+		{">*", nil},
+		{"<*", nil},
+		{"> *", nil},
+		{">=1.0.0 <x", nil},
+		{"x.2.3", nil},
+		{"x.2", nil},
+		{"x.x.3", nil},
+		{"*.2.3 || 1.2.3", nil},
 		// More range tests
 		{">=11 <12", []tv{
 			{"10.1.4", false},
@@ -541,6 +800,30 @@ func TestParseRange(t *testing.T) {
 			{"3.9.2", true},
 			{"2.1.3", true},
 		}},
+		// Hyphen ranges with prereleases
+		{"1.2.3-rc.1 - 2.0.0-rc.1", []tv{
+			{"1.2.3-beta", false},
+			{"1.2.3-rc.1", true},
+			{"1.2.3", true},
+			{"1.9.9", true},
+			{"2.0.0-beta", true},
+			{"2.0.0-rc.1", true},
+			{"2.0.0-rc.2", false},
+			{"2.0.0", false},
+		}},
+		{"1.2.3-pre-1 - 2.0.0", []tv{
+			{"1.2.3-pre", false},
+			{"1.2.3-pre-1", true},
+			{"2.0.0", true},
+			{"2.0.1", false},
+		}},
+		{"1.2.3-rc.1 - 2.0.0 || 3.0.0-rc.1-a - 3.0.0", []tv{
+			{"1.2.3-rc.1", true},
+			{"2.5.0", false},
+			{"3.0.0-rc.1", false},
+			{"3.0.0-rc.1-a", true},
+			{"3.0.0", true},
+		}},
 	}
 
 	for _, tc := range tests {
@@ -559,6 +842,888 @@ func TestParseRange(t *testing.T) {
 	}
 }
 
+func TestParseCargoRange(t *testing.T) {
+	type tv struct {
+		v string
+		b bool
+	}
+	tests := []struct {
+		i string
+		t []tv
+	}{
+		// bare versions are caret requirements
+		{"1.2.3", []tv{
+			{"1.2.2", false},
+			{"1.2.3", true},
+			{"1.8.9", true},
+			{"2.0.0", false},
+		}},
+		{"0.2.3", []tv{
+			{"0.2.3", true},
+			{"0.2.9", true},
+			{"0.3.0", false},
+		}},
+		{"1.2", []tv{
+			{"1.2.0", true},
+			{"1.9.0", true},
+			{"2.0.0", false},
+		}},
+		{"=1.2.3", []tv{
+			{"1.2.3", true},
+			{"1.2.4", false},
+		}},
+		{"~1.2", []tv{
+			{"1.2.0", true},
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
+		{"*", []tv{
+			{"0.0.1", true},
+			{"10.2.0", true},
+		}},
+		{"1.*", []tv{
+			{"1.0.0", true},
+			{"1.9.9", true},
+			{"2.0.0", false},
+		}},
+		{">=1.2.0, <1.5.0", []tv{
+			{"1.1.9", false},
+			{"1.2.0", true},
+			{"1.4.9", true},
+			{"1.5.0", false},
+		}},
+		{"1.2, !=1.3.0", []tv{
+			{"1.2.0", true},
+			{"1.3.0", false},
+			{"1.3.1", true},
+		}},
+		// errors
+		{"", nil},
+		{">=1.0.0,", nil},
+		{"1.0.0 || 2.0.0", nil},
+		{"foo", nil},
+	}
+
+	for _, tc := range tests {
+		r, err := ParseCargoRange(tc.i)
+		if err != nil {
+			if tc.t != nil {
+				t.Errorf("Error parsing range %q: %s", tc.i, err)
+			}
+			continue
+		}
+		if tc.t == nil {
+			t.Errorf("Expected error parsing range %q, got none", tc.i)
+			continue
+		}
+		for _, tvc := range tc.t {
+			v := MustParse(tvc.v)
+			if res := r(v); res != tvc.b {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", tc.i, tvc.v, tvc.b, res)
+			}
+		}
+	}
+
+	// A bare version is a caret requirement for Cargo, but exact otherwise
+	v := MustParse("1.3.0")
+	if r := MustParseRange("1.2.3"); r(v) {
+		t.Errorf("Expected ParseRange(%q) not to match %q", "1.2.3", v)
+	}
+	if r, _ := ParseCargoRange("1.2.3"); !r(v) {
+		t.Errorf("Expected ParseCargoRange(%q) to match %q", "1.2.3", v)
+	}
+}
+
+func TestParseNaturalRange(t *testing.T) {
+	tests := []struct {
+		i string
+		o string
+	}{
+		{"greater than 1.2.3", ">1.2.3"},
+		{"at least 2.0.0", ">=2.0.0"},
+		{"less than 3.0.0", "<3.0.0"},
+		{"at most 3.0.0", "<=3.0.0"},
+		{"greater than or equal to 1.2.3", ">=1.2.3"},
+		{"less than or equal to 1.2.3", "<=1.2.3"},
+		{"exactly 1.2.3-RC.1", "1.2.3-RC.1"},
+		{"not 1.2.3", "!=1.2.3"},
+		{"between 1.0.0 and 2.0.0", ">=1.0.0 <=2.0.0"},
+		{"  Greater  Than 1.2.3 ", ">1.2.3"},
+		{"BETWEEN 1.0.0 AND 2.0.0", ">=1.0.0 <=2.0.0"},
+		// errors
+		{"", ""},
+		{"1.2.3", ""},
+		{"more than 1.2.3", ""},
+		{"greater than", ""},
+		{"greater than foo", ""},
+		{"greater than 1.2.3 || 2.0.0", ""},
+		{"at least 1.0.0 <0.5.0", ""},
+		{"between 1.0.0", ""},
+		{"between 1.0.0 and", ""},
+		{"between 1.0.0 or 2.0.0", ""},
+		{"between 1.0.0||3.0.0 and 2.0.0", ""},
+	}
+	for _, tc := range tests {
+		r, err := ParseNaturalRange(tc.i)
+		if tc.o == "" {
+			if err == nil {
+				t.Errorf("Expected error parsing range %q, got none", tc.i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+			continue
+		}
+		expected := MustParseRange(tc.o)
+		for _, pv := range append(probeVersions, "1.2.3-RC.1") {
+			v := MustParse(pv)
+			if r(v) != expected(v) {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", tc.i, pv, expected(v), r(v))
+			}
+		}
+	}
+}
+
+func TestParseRangeWithSyntax(t *testing.T) {
+	type tv struct {
+		v string
+		b bool
+	}
+	tests := []struct {
+		i      string
+		syntax RangeSyntax
+		t      []tv
+	}{
+		{">=1.0,<2.0", SyntaxComposer, []tv{
+			{"0.9.0", false},
+			{"1.0.0", true},
+			{"1.9.9", true},
+			{"2.0.0", false},
+		}},
+		{">=1.0, <2.0 || >=3.0", SyntaxComposer, []tv{
+			{"1.5.0", true},
+			{"2.5.0", false},
+			{"3.1.0", true},
+		}},
+		{">=1.0 <2.0", SyntaxComposer, []tv{
+			{"1.5.0", true},
+			{"2.0.0", false},
+		}},
+		{">=1.0 <2.0", SyntaxNPM, []tv{
+			{"1.5.0", true},
+			{"2.0.0", false},
+		}},
+		{"^1.2.3 || 3.x", SyntaxNPM, []tv{
+			{"1.5.0", true},
+			{"2.0.0", false},
+			{"3.4.0", true},
+		}},
+		{">=1.0, <2.0 | >=3.0", SyntaxComposer, []tv{
+			{"1.5.0", true},
+			{"2.5.0", false},
+			{"3.1.0", true},
+		}},
+		// Composer's tilde allows later minor versions with only major and minor
+		{"~1.2", SyntaxComposer, []tv{
+			{"1.1.9", false},
+			{"1.2.0", true},
+			{"1.5.0", true},
+			{"2.0.0", false},
+		}},
+		{"~ 0.2, !=0.4.0", SyntaxComposer, []tv{
+			{"0.2.0", true},
+			{"0.4.0", false},
+			{"0.9.0", true},
+			{"1.0.0", false},
+		}},
+		{"~1.2", SyntaxNPM, []tv{
+			{"1.2.0", true},
+			{"1.5.0", false},
+		}},
+		{"~1.2.3", SyntaxComposer, []tv{
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
+		{"~1", SyntaxComposer, []tv{
+			{"1.9.0", true},
+			{"2.0.0", false},
+		}},
+		// errors
+		{">=1.0,<2.0", SyntaxNPM, nil},
+		{">=1.0,", SyntaxComposer, nil},
+		{",<2.0", SyntaxComposer, nil},
+		{">=1.0 |", SyntaxComposer, nil},
+		{"", SyntaxComposer, nil},
+		{">=1.0", RangeSyntax(42), nil},
+	}
+
+	for _, tc := range tests {
+		r, err := ParseRangeWithSyntax(tc.i, tc.syntax)
+		if err != nil {
+			if tc.t != nil {
+				t.Errorf("Error parsing range %q: %s", tc.i, err)
+			}
+			continue
+		}
+		if tc.t == nil {
+			t.Errorf("Expected error parsing range %q, got none", tc.i)
+			continue
+		}
+		for _, tvc := range tc.t {
+			v := MustParse(tvc.v)
+			if res := r(v); res != tvc.b {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", tc.i, tvc.v, tvc.b, res)
+			}
+		}
+	}
+}
+
+func TestParseRangeWithOptions(t *testing.T) {
+	type tv struct {
+		v string
+		b bool
+	}
+	tests := []struct {
+		i    string
+		opts RangeOptions
+		t    []tv
+	}{
+		{"1.2.x", RangeOptions{}, []tv{
+			{"1.2.0", true},
+			{"1.2.3-beta", true},
+			{"1.3.0", false},
+		}},
+		{"1.2.x", RangeOptions{WildcardStableOnly: true}, []tv{
+			{"1.2.0", true},
+			{"1.2.3", true},
+			{"1.2.3-beta", false},
+			{"1.3.0", false},
+		}},
+		{"1.2", RangeOptions{WildcardStableOnly: true}, []tv{
+			{"1.2.3", true},
+			{"1.2.3-beta", false},
+		}},
+		{"=1.x", RangeOptions{WildcardStableOnly: true}, []tv{
+			{"1.5.0", true},
+			{"1.5.0-rc.1", false},
+		}},
+		// only groups with a wildcard patch exclude prereleases
+		{"1.2.x || >=2.0.0-0 <3.0.0", RangeOptions{WildcardStableOnly: true}, []tv{
+			{"1.2.3-beta", false},
+			{"1.2.3", true},
+			{"2.0.0-rc.1", true},
+			{"2.5.0", true},
+		}},
+		{">=1.2.0 <1.3.0", RangeOptions{WildcardStableOnly: true}, []tv{
+			{"1.2.3-beta", true},
+		}},
+		{"*", RangeOptions{WildcardStableOnly: true}, []tv{
+			{"1.2.3-beta", true},
+		}},
+		{"1.2.3", RangeOptions{WildcardStableOnly: true}, []tv{
+			{"1.2.3", true},
+			{"1.2.3-beta", false},
+		}},
+		{"", RangeOptions{EmptyMeansAny: true}, []tv{
+			{"0.0.0", true},
+			{"1.2.3", true},
+			{"1.2.3-beta", true},
+		}},
+		{" \t", RangeOptions{EmptyMeansAny: true}, []tv{
+			{"1.2.3", true},
+		}},
+		{"1.2.x", RangeOptions{EmptyMeansAny: true}, []tv{
+			{"1.2.3", true},
+			{"1.2.3-beta", true},
+			{"1.3.0", false},
+		}},
+		{"", RangeOptions{EmptyMeansAny: true, WildcardStableOnly: true}, []tv{
+			{"1.2.3", true},
+			{"1.2.3-beta", true},
+		}},
+		// errors
+		{"", RangeOptions{}, nil},
+		{" \t", RangeOptions{}, nil},
+		{"foo", RangeOptions{}, nil},
+		{"foo", RangeOptions{EmptyMeansAny: true}, nil},
+		{"1.2.3 || ", RangeOptions{EmptyMeansAny: true}, nil},
+	}
+
+	for _, tc := range tests {
+		r, err := ParseRangeWithOptions(tc.i, tc.opts)
+		if err != nil {
+			if tc.t != nil {
+				t.Errorf("Error parsing range %q: %s", tc.i, err)
+			}
+			continue
+		}
+		if tc.t == nil {
+			t.Errorf("Expected error parsing range %q, got none", tc.i)
+			continue
+		}
+		for _, tvc := range tc.t {
+			v := MustParse(tvc.v)
+			if res := r(v); res != tvc.b {
+				t.Errorf("Invalid for case %q %+v matching %q: Expected %t, got: %t", tc.i, tc.opts, tvc.v, tvc.b, res)
+			}
+		}
+	}
+
+	if _, err := ParseRangeWithOptions("", RangeOptions{}); err != ErrEmptyRange {
+		t.Errorf("Expected ErrEmptyRange for empty range, got %v", err)
+	}
+
+	// the zero RangeOptions behave like ParseRange
+	for _, i := range []string{"1.2.x", "1.x || ~2.1.0", "*", ">=1.2.3 <2.0.0-0", "!=1.x"} {
+		r, err := ParseRangeWithOptions(i, RangeOptions{})
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", i, err)
+			continue
+		}
+		pr := MustParseRange(i)
+		for _, pv := range probeVersions {
+			if v := MustParse(pv); r(v) != pr(v) {
+				t.Errorf("Invalid for case %q matching %q: Expected %t like ParseRange, got: %t", i, pv, pr(v), r(v))
+			}
+		}
+	}
+
+	// ParseRange compares by precedence only
+	if r := MustParseRange("1.2.x"); !r(MustParse("1.2.3-beta")) {
+		t.Errorf("Expected ParseRange(%q) to match %q", "1.2.x", "1.2.3-beta")
+	}
+}
+
+func TestParseRangeWithOptionsStrict(t *testing.T) {
+	strict := RangeOptions{Strict: true}
+	valid := []string{
+		">=1.0.0",
+		">= 1.0.0 <  2.0.0",
+		">=v1.0.0 <=2.0.0-rc.1+build",
+		"==1.2.3 || !=1.3.0",
+		"!1.2.3",
+		"~1.2.3 ^1.2.0",
+		"~> 1.2",
+		"^ 1.2.x",
+		"1.x || 2.*.* || X || *",
+		">=*",
+		">1.x",
+		"1.2.3-rc.1 - 2.0.0",
+		"1.2 - 2",
+	}
+	for _, s := range valid {
+		r, err := ParseRangeWithOptions(s, strict)
+		if err != nil {
+			t.Errorf("Unexpected error for strict range %q: %s", s, err)
+			continue
+		}
+		// the same versions as without Strict
+		lenient := MustParseRange(s)
+		for _, v := range []string{"0.9.0", "1.0.0", "1.2.3", "1.2.4-rc.1", "1.5.0", "2.0.0", "2.5.0"} {
+			if res, want := r(MustParse(v)), lenient(MustParse(v)); res != want {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", s, v, want, res)
+			}
+		}
+	}
+
+	invalid := []struct {
+		s   string
+		err string
+	}{
+		{">=1.0.0 garbage", `Invalid comparator "garbage" in range ">=1.0.0 garbage"`},
+		{">= foo", `Invalid comparator ">=foo" in range ">= foo"`},
+		{"1.2.3 >=", `Invalid comparator ">=" in range "1.2.3 >="`},
+		{"~1.2.3foo", `Invalid comparator "~1.2.3foo" in range "~1.2.3foo"`},
+		{"x.x.x-foo", `Invalid comparator "x.x.x-foo" in range "x.x.x-foo"`},
+		{"1.2.x+build", `Invalid comparator "1.2.x+build" in range "1.2.x+build"`},
+		{"1.2.3 || 1.2.3.4", `Invalid comparator "1.2.3.4" in range "1.2.3 || 1.2.3.4"`},
+		{"<*", `Invalid comparator "<*" in range "<*", a wildcard can not follow > or <`},
+		{"1.2.3 > x", `Invalid comparator ">x" in range "1.2.3 > x", a wildcard can not follow > or <`},
+		{">=1.2.3 - 2.0.0", `Invalid comparator ">=1.2.3" in range ">=1.2.3 - 2.0.0", the versions of a hyphen range can not have an operator`},
+		{"=<1.2.3", `Invalid comparator "=<1.2.3" in range "=<1.2.3"`},
+	}
+	for _, tc := range invalid {
+		if _, err := ParseRangeWithOptions(tc.s, strict); err == nil {
+			t.Errorf("Expected error for strict range %q, got none", tc.s)
+		} else if err.Error() != tc.err {
+			t.Errorf("Invalid for case %q: Expected error %q, got: %q", tc.s, tc.err, err)
+		}
+	}
+
+	// without Strict some of them are accepted
+	for _, s := range []string{"x.x.x-foo"} {
+		if _, err := ParseRangeWithOptions(s, RangeOptions{}); err != nil {
+			t.Errorf("Unexpected error for range %q: %s", s, err)
+		}
+	}
+}
+
+func TestParseRangeExprNegatedXRange(t *testing.T) {
+	tests := []struct {
+		i string
+		o string
+	}{
+		{"!=1.x", "<1.0.0 || >=2.0.0"},
+		{"!1.2.x", "<1.2.0 || >=1.3.0"},
+		{"!=1", "<1.0.0 || >=2.0.0"},
+		{">=0.5.0 !=1.x", ">=0.5.0 <1.0.0 || >=0.5.0 >=2.0.0"},
+		{"!=1.2.3", "!=1.2.3"},
+		{"!=1.x || 1.5.0", "<1.0.0 || >=2.0.0 || 1.5.0"},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+		} else if o := e.String(); o != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.i, tc.o, o)
+		}
+	}
+}
+
+func TestParseRangeExprEqualityWildcards(t *testing.T) {
+	tests := []struct {
+		i string
+		o string
+	}{
+		{"=1.x", ">=1.0.0 <2.0.0"},
+		{"=1.*", ">=1.0.0 <2.0.0"},
+		{"==1.2.x", ">=1.2.0 <1.3.0"},
+		{"== 2.0.x", ">=2.0.0 <2.1.0"},
+		{"=1.2.3", "1.2.3"},
+		{"==1.2.3", "1.2.3"},
+		{">=1.2.3 ==1.2.x", ">=1.2.3 >=1.2.0 <1.3.0"},
+		{"1.0.0 || ==1.2.x", "1.0.0 || >=1.2.0 <1.3.0"},
+	}
+	for _, tc := range tests {
+		e, err := ParseRangeExpr(tc.i)
+		if err != nil {
+			t.Errorf("Error parsing range %q: %s", tc.i, err)
+		} else if o := e.String(); o != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.i, tc.o, o)
+		}
+	}
+
+	// only a standalone "==" is an equality operator
+	for _, s := range []string{">==1.2.3", "<==1.2.3", "!==1.2.3", "===1.2.3", "1.0.0 ===1.2.3"} {
+		if _, err := ParseRangeExpr(s); err == nil || !strings.Contains(err.Error(), "Could not parse comparator") {
+			t.Errorf("Expected comparator error for %q, got %v", s, err)
+		}
+	}
+}
+
+func TestNewRange(t *testing.T) {
+	lower, upper := MustParse("1.2.3"), MustParse("2.0.0")
+	tests := []struct {
+		r Range
+		t map[string]bool
+	}{
+		{NewRange(lower, true, upper, false), map[string]bool{
+			"1.2.2": false, "1.2.3": true, "1.9.9": true, "2.0.0": false,
+		}},
+		{NewRange(lower, false, upper, true), map[string]bool{
+			"1.2.2": false, "1.2.3": false, "1.9.9": true, "2.0.0": true, "2.0.1": false,
+		}},
+		{NewRange(lower, true, Version{}, false), map[string]bool{
+			"1.2.2": false, "1.2.3": true, "2.0.0": true, "100.0.0": true,
+		}},
+		{GT(lower), map[string]bool{"1.2.2": false, "1.2.3": false, "1.2.4": true}},
+		{GTE(lower), map[string]bool{"1.2.2": false, "1.2.3": true, "1.2.4": true}},
+		{LT(lower), map[string]bool{"1.2.2": true, "1.2.3": false, "1.2.4": false}},
+		{LTE(lower), map[string]bool{"1.2.2": true, "1.2.3": true, "1.2.4": false}},
+	}
+	for i, tc := range tests {
+		for v, b := range tc.t {
+			if res := tc.r(MustParse(v)); res != b {
+				t.Errorf("Invalid for case %d matching %q: Expected %t, got: %t", i, v, b, res)
+			}
+		}
+	}
+}
+
+func TestPinToMinor(t *testing.T) {
+	tests := []struct {
+		v string
+		b bool
+	}{
+		{"1.2.2", false},
+		{"1.2.3-rc.1", false},
+		{"1.2.3", true},
+		{"1.2.4", true},
+		{"1.2.99", true},
+		{"1.3.0-alpha", true},
+		{"1.3.0", false},
+		{"2.0.0", false},
+	}
+	r := PinToMinor(MustParse("1.2.3"))
+	for _, tc := range tests {
+		if res := r(MustParse(tc.v)); res != tc.b {
+			t.Errorf("Invalid for case %q: Expected %t, got: %t", tc.v, tc.b, res)
+		}
+	}
+}
+
+func TestStableFrom(t *testing.T) {
+	tests := []struct {
+		v string
+		b bool
+	}{
+		{"1.2.2", false},
+		{"1.2.3-rc.1", false},
+		{"1.2.3", true},
+		{"1.2.3+build", true},
+		{"1.5.0", true},
+		{"2.0.0-beta", false},
+		{"2.0.0", true},
+	}
+	r := StableFrom(MustParse("1.2.3"))
+	for _, tc := range tests {
+		if res := r(MustParse(tc.v)); res != tc.b {
+			t.Errorf("Invalid for case %q: Expected %t, got: %t", tc.v, tc.b, res)
+		}
+	}
+}
+
+func TestPrereleaseRange(t *testing.T) {
+	tests := []struct {
+		v string
+		b bool
+	}{
+		{"1.2.0-rc.1", true},
+		{"1.2.0-0", true},
+		{"1.2.0-alpha+build", true},
+		{"1.2.0", false},
+		{"1.2.0+build", false},
+		{"1.2.1-rc.1", false},
+		{"1.1.9", false},
+		{"1.1.9-rc.1", false},
+	}
+	for _, s := range []string{"1.2.0", "1.2.0-beta"} {
+		r := PrereleaseRange(MustParse(s))
+		for _, tc := range tests {
+			if res := r(MustParse(tc.v)); res != tc.b {
+				t.Errorf("Invalid for case %q matching %q: Expected %t, got: %t", s, tc.v, tc.b, res)
+			}
+		}
+	}
+}
+
+func TestEvenOddMinorsOf(t *testing.T) {
+	tests := []struct {
+		v    string
+		even bool
+		odd  bool
+	}{
+		{"4.0.0", true, false},
+		{"4.2.0", true, false},
+		{"4.2.7-rc.1", true, false},
+		{"4.3.0", false, true},
+		{"4.11.2", false, true},
+		{"3.2.0", false, false},
+		{"5.3.0", false, false},
+	}
+	even, odd := EvenMinorsOf(4), OddMinorsOf(4)
+	for _, tc := range tests {
+		v := MustParse(tc.v)
+		if res := even(v); res != tc.even {
+			t.Errorf("Invalid for case %q matching even minors: Expected %t, got: %t", tc.v, tc.even, res)
+		}
+		if res := odd(v); res != tc.odd {
+			t.Errorf("Invalid for case %q matching odd minors: Expected %t, got: %t", tc.v, tc.odd, res)
+		}
+	}
+}
+
+func TestRangeFor(t *testing.T) {
+	type tv struct {
+		v string
+		b bool
+	}
+	tests := []struct {
+		v      string
+		policy Policy
+		t      []tv
+	}{
+		{"1.2.3", PatchOnly, []tv{
+			{"1.2.2", false},
+			{"1.2.3", true},
+			{"1.2.9", true},
+			{"1.3.0", false},
+		}},
+		{"1.2.3", MinorAndPatch, []tv{
+			{"1.2.2", false},
+			{"1.2.3", true},
+			{"1.9.0", true},
+			{"2.0.0", false},
+		}},
+		{"1.2.3", MajorCompatible, []tv{
+			{"1.2.2", false},
+			{"1.9.0", true},
+			{"2.0.0", false},
+		}},
+		{"0.2.3", MinorAndPatch, []tv{
+			{"0.2.3", true},
+			{"0.9.0", true},
+			{"1.0.0", false},
+		}},
+		{"0.2.3", MajorCompatible, []tv{
+			{"0.2.3", true},
+			{"0.2.9", true},
+			{"0.3.0", false},
+		}},
+		{"0.0.3", MajorCompatible, []tv{
+			{"0.0.3", true},
+			{"0.0.4", false},
+		}},
+		{"1.2.3", AnyNewer, []tv{
+			{"1.2.2", false},
+			{"1.2.3", true},
+			{"10.0.0", true},
+		}},
+		{"1.2.3", Policy(42), []tv{
+			{"1.2.3", false},
+			{"1.2.4", false},
+		}},
+	}
+	for _, tc := range tests {
+		r := RangeFor(MustParse(tc.v), tc.policy)
+		for _, tvc := range tc.t {
+			if res := r(MustParse(tvc.v)); res != tvc.b {
+				t.Errorf("Invalid for case %q with policy %d matching %q: Expected %t, got: %t", tc.v, tc.policy, tvc.v, tvc.b, res)
+			}
+		}
+	}
+}
+
+func TestCaretCompatible(t *testing.T) {
+	tests := []struct {
+		base  string
+		other string
+		b     bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.2", false},
+		{"1.2.3", "1.8.9", true},
+		{"1.2.3", "2.0.0", false},
+		{"1.2.3", "1.2.4-rc.1", true},
+		{"0.2.3", "0.2.3", true},
+		{"0.2.3", "0.2.2", false},
+		{"0.2.3", "0.2.9", true},
+		{"0.2.3", "0.3.1", false},
+		{"0.2.3", "1.0.0", false},
+		{"0.0.3", "0.0.3", true},
+		{"0.0.3", "0.0.4", false},
+		{"0.0.0", "0.0.1", false},
+		{"1.2.3-rc.1", "1.2.3-rc.2", true},
+		{"1.2.3-rc.1", "1.2.3-beta", false},
+		{"1.2.3+build", "1.2.3", true},
+	}
+	for _, tc := range tests {
+		base, other := MustParse(tc.base), MustParse(tc.other)
+		if res := base.CaretCompatible(other); res != tc.b {
+			t.Errorf("Invalid for case %q compatible with %q: Expected %t, got: %t", tc.other, tc.base, tc.b, res)
+		}
+		// agrees with the parsed caret range
+		if res := MustParseRange("^" + tc.base)(other); res != tc.b {
+			t.Errorf("Invalid for range %q matching %q: Expected %t, got: %t", "^"+tc.base, tc.other, tc.b, res)
+		}
+	}
+}
+
+func TestRecommendedRange(t *testing.T) {
+	tests := []struct {
+		v string
+		o string
+	}{
+		{"1.2.3", "^1.2.3"},
+		{"0.2.3", "^0.2.3"},
+		{"0.0.3", "^0.0.3"},
+		{"2.0.0-rc.1", "^2.0.0-rc.1"},
+		{"1.2.3+build.5", "^1.2.3"},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.v)
+		o := RecommendedRange(v)
+		if o != tc.o {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.v, tc.o, o)
+			continue
+		}
+		if r := MustParseRange(o); !r(v) {
+			t.Errorf("Expected recommended range %q to match %q", o, tc.v)
+		}
+	}
+}
+
+func TestExcludeAdvisory(t *testing.T) {
+	bad := []Version{MustParse("1.4.1"), MustParse("1.4.1+build"), MustParse("2.1.0"), MustParse("3.0.0")}
+	tests := []struct {
+		base string
+		o    string
+	}{
+		{"^1.2.0", "^1.2.0 !=1.4.1"},
+		{"^1.2.0 || ^2.0.0", "^1.2.0 !=1.4.1 || ^2.0.0 !=2.1.0"},
+		{">=1.0.0", ">=1.0.0 !=1.4.1 !=2.1.0 !=3.0.0"},
+		{"~1.2.0", "~1.2.0"},
+		{"!=1.x", "!=1.x !=2.1.0 !=3.0.0"},
+		{"1.0.0 - 2.0.0", ">=1.0.0 <=2.0.0 !=1.4.1"},
+		{"1.0.0 - 2.0.0 || 2.1.0 - 3", ">=1.0.0 <=2.0.0 !=1.4.1 || >=2.1.0 <4.0.0 !=2.1.0 !=3.0.0"},
+		{"1.2 - 1.5", ">=1.2.0 <1.6.0 !=1.4.1"},
+		// errors
+		{"", ""},
+		{"foo", ""},
+	}
+	for _, tc := range tests {
+		o, err := ExcludeAdvisory(tc.base, bad)
+		if tc.o == "" {
+			if err == nil {
+				t.Errorf("Expected error for base %q, got none", tc.base)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error for base %q: %s", tc.base, err)
+			continue
+		}
+		if o != tc.o {
+			t.Errorf("Invalid for base %q: Expected %q, got: %q", tc.base, tc.o, o)
+		}
+		base, r := MustParseRange(tc.base), MustParseRange(o)
+		for _, v := range bad {
+			if r(v) {
+				t.Errorf("Invalid for base %q: Expected %q not to match %q", tc.base, o, v)
+			}
+		}
+		for _, pv := range probeVersions {
+			if v := MustParse(pv); r(v) != base(v) && !v.EQ(bad[3]) {
+				t.Errorf("Invalid for base %q matching %q: Expected %t, got: %t", tc.base, pv, base(v), r(v))
+			}
+		}
+	}
+}
+
+func TestSecurityBaseline(t *testing.T) {
+	tests := []struct {
+		v         string
+		sameMajor bool
+		any       bool
+	}{
+		{"1.4.0", false, false},
+		{"1.4.1", true, true},
+		{"1.9.0", true, true},
+		{"2.0.0", false, true},
+		{"3.1.0", false, true},
+	}
+	minimum := MustParse("1.4.1")
+	withinMajor, crossMajor := SecurityBaseline(minimum, true), SecurityBaseline(minimum, false)
+	for _, tc := range tests {
+		v := MustParse(tc.v)
+		if res := withinMajor(v); res != tc.sameMajor {
+			t.Errorf("Invalid for case %q within major: Expected %t, got: %t", tc.v, tc.sameMajor, res)
+		}
+		if res := crossMajor(v); res != tc.any {
+			t.Errorf("Invalid for case %q across majors: Expected %t, got: %t", tc.v, tc.any, res)
+		}
+	}
+}
+
+func TestSatisfiesAllAny(t *testing.T) {
+	ge1 := MustParseRange(">=1.0.0")
+	lt2 := MustParseRange("<2.0.0")
+	is3 := MustParseRange("3.0.0")
+	tests := []struct {
+		v      string
+		ranges []Range
+		all    bool
+		any    bool
+	}{
+		{"1.5.0", nil, true, false},
+		{"1.5.0", []Range{ge1}, true, true},
+		{"1.5.0", []Range{ge1, lt2}, true, true},
+		{"1.5.0", []Range{ge1, lt2, is3}, false, true},
+		{"3.0.0", []Range{ge1, lt2, is3}, false, true},
+		{"0.5.0", []Range{ge1, is3}, false, false},
+	}
+	for _, tc := range tests {
+		v := MustParse(tc.v)
+		if res := SatisfiesAll(v, tc.ranges...); res != tc.all {
+			t.Errorf("SatisfiesAll for %q with %d ranges: Expected %t, got: %t", tc.v, len(tc.ranges), tc.all, res)
+		}
+		if res := SatisfiesAny(v, tc.ranges...); res != tc.any {
+			t.Errorf("SatisfiesAny for %q with %d ranges: Expected %t, got: %t", tc.v, len(tc.ranges), tc.any, res)
+		}
+	}
+}
+
+func TestWhichRanges(t *testing.T) {
+	named := map[string]Range{
+		"stable":  MustParseRange(">=1.0.0"),
+		"legacy":  MustParseRange("<2.0.0"),
+		"current": MustParseRange("^2.0.0"),
+		"one":     MustParseRange("1.x"),
+	}
+	tests := []struct {
+		v     string
+		names []string
+	}{
+		{"0.9.0", []string{"legacy"}},
+		{"1.5.0", []string{"legacy", "one", "stable"}},
+		{"2.1.0", []string{"current", "stable"}},
+		{"3.0.0", []string{"stable"}},
+	}
+	for _, tc := range tests {
+		names := WhichRanges(MustParse(tc.v), named)
+		if strings.Join(names, ",") != strings.Join(tc.names, ",") {
+			t.Errorf("Invalid for case %q: Expected %q, got: %q", tc.v, tc.names, names)
+		}
+	}
+	if names := WhichRanges(MustParse("1.0.0"), nil); len(names) != 0 {
+		t.Errorf("Expected no names for no ranges, got: %q", names)
+	}
+}
+
+func TestRangeForEach(t *testing.T) {
+	vs := []Version{
+		MustParse("1.0.0"),
+		MustParse("2.1.0"),
+		MustParse("1.5.0"),
+		MustParse("3.0.0"),
+		MustParse("1.9.0"),
+	}
+	r := MustParseRange("^1.0.0")
+
+	var all []string
+	RangeForEach(vs, r, func(v Version) bool {
+		all = append(all, v.String())
+		return true
+	})
+	if strings.Join(all, " ") != "1.0.0 1.5.0 1.9.0" {
+		t.Errorf("Expected all matching versions in order, got %q", all)
+	}
+
+	// stops after the second match
+	var first []string
+	RangeForEach(vs, r, func(v Version) bool {
+		first = append(first, v.String())
+		return len(first) < 2
+	})
+	if strings.Join(first, " ") != "1.0.0 1.5.0" {
+		t.Errorf("Expected early termination after two versions, got %q", first)
+	}
+
+	calls := 0
+	RangeForEach(vs, MustParseRange(">=4.0.0"), func(Version) bool {
+		calls++
+		return true
+	})
+	RangeForEach(nil, r, func(Version) bool {
+		calls++
+		return true
+	})
+	if calls != 0 {
+		t.Errorf("Expected no calls without matches, got %d", calls)
+	}
+}
+
 func TestMustParseRange(t *testing.T) {
 	testCase := ">1.2.2 <1.2.4 || >=2.0.0 <3.0.0"
 	r := MustParseRange(testCase)
@@ -635,3 +1800,33 @@ func BenchmarkRangeMatchComplex(b *testing.B) {
 		r(v)
 	}
 }
+
+func TestNormalizeRange(t *testing.T) {
+	tests := []struct {
+		inputs []string
+		o      string
+	}{
+		{[]string{">=1.2.3", ">= 1.2.3", ">=v1.2.3", ">=  v1.2.3", " >=1.2.3 "}, ">=1.2.3"},
+		{[]string{"1.2.3", "v1.2.3", "=1.2.3", "==1.2.3", "= 1.2.3", "== v1.2.3"}, "1.2.3"},
+		{[]string{"!=1.2.3", "!1.2.3", "!= 1.2.3", "!=v1.2.3"}, "!=1.2.3"},
+		{[]string{">=2.0.0 <3.0.0 || 1.2.3", ">= 2.0.0  <  3.0.0||==1.2.3", ">=v2.0.0 <v3.0.0 || v1.2.3"}, ">=2.0.0 <3.0.0 || 1.2.3"},
+		{[]string{"^1.2.3", ">=1.2.3 <2.0.0"}, ">=1.2.3 <2.0.0"},
+		{[]string{"1.2.x", "1.2", "~1.2.0"}, ">=1.2.0 <1.3.0"},
+	}
+	for _, tc := range tests {
+		for _, s := range tc.inputs {
+			o, err := NormalizeRange(s)
+			if err != nil {
+				t.Errorf("Unexpected error for %q: %s", s, err)
+			} else if o != tc.o {
+				t.Errorf("Invalid for case %q: Expected %q, got: %q", s, tc.o, o)
+			}
+		}
+	}
+
+	for _, s := range []string{"", ">=1.2.3.4", ">>1.2.3"} {
+		if o, err := NormalizeRange(s); err == nil {
+			t.Errorf("Expected error for %q, got %q", s, o)
+		}
+	}
+}
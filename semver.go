@@ -3,6 +3,9 @@ package semver
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/bits"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -20,7 +23,9 @@ var SpecVersion = Version{
 	Patch: 0,
 }
 
-// Version represents a semver compatible version
+// Version represents a semver compatible version.
+// As it holds slices, a Version is not comparable with == and can not be
+// used as a map key, use Key instead.
 type Version struct {
 	Major uint64
 	Minor uint64
@@ -29,9 +34,146 @@ type Version struct {
 	Build []string //No Precedence
 }
 
+// Clone returns a deep copy of v. Assigning a Version copies the Pre and
+// Build slices by reference, so modifying their elements on the copy also
+// modifies the original. Clone the version before doing so.
+//
+// The increment methods and SetPrerelease never modify the elements of Pre
+// or Build, they only replace the slices, and are safe to use on copies.
+func (v Version) Clone() Version {
+	c := v
+	if v.Pre != nil {
+		c.Pre = make([]PRVersion, len(v.Pre))
+		copy(c.Pre, v.Pre)
+	}
+	if v.Build != nil {
+		c.Build = make([]string, len(v.Build))
+		copy(c.Build, v.Build)
+	}
+	return c
+}
+
+// Core returns the major, minor and patch version of v, without prerelease
+// and build meta data.
+func (v Version) Core() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// WithoutBuild returns a copy of v without build metadata.
+func (v Version) WithoutBuild() Version {
+	c := v.Clone()
+	c.Build = nil
+	return c
+}
+
+// WithoutPrerelease returns a copy of v without prerelease versions.
+func (v Version) WithoutPrerelease() Version {
+	c := v.Clone()
+	c.Pre = nil
+	return c
+}
+
+// CanonicalizeBuild returns a copy of v with its build identifiers sorted
+// lexically, so versions differing only in the order of their build
+// metadata get the same String, e.g. for use as a map key. This deviates
+// from the spec, which keeps the build metadata as written, so the result
+// should not be published as the version itself.
+func (v Version) CanonicalizeBuild() Version {
+	c := v.Clone()
+	sort.Strings(c.Build)
+	return c
+}
+
+// WithMajor returns a copy of v with the major version set to n. Unlike the
+// Increment methods, the minor and patch versions, prerelease and build
+// metadata are left untouched.
+func (v Version) WithMajor(n uint64) Version {
+	c := v.Clone()
+	c.Major = n
+	return c
+}
+
+// WithMinor returns a copy of v with the minor version set to n. All other
+// components are left untouched.
+func (v Version) WithMinor(n uint64) Version {
+	c := v.Clone()
+	c.Minor = n
+	return c
+}
+
+// WithPatch returns a copy of v with the patch version set to n. All other
+// components are left untouched.
+func (v Version) WithPatch(n uint64) Version {
+	c := v.Clone()
+	c.Patch = n
+	return c
+}
+
+// LowestPrerelease returns the core of v with the prerelease "0", which is
+// the lowest possible prerelease of the core. It sorts below every other
+// prerelease of the core, e.g. 1.2.3-0 < 1.2.3-alpha.
+func (v Version) LowestPrerelease() Version {
+	c := v.Core()
+	c.Pre = []PRVersion{{VersionNum: 0, IsNum: true}}
+	return c
+}
+
+// WithLowestPrerelease returns a copy of v with the prerelease replaced by
+// "0", the lowest prerelease of the core like for LowestPrerelease, e.g.
+// 1.2.0-0 for 1.2.0 or 1.2.0-rc.1. Like for the other With methods, build
+// meta data is left untouched.
+func (v Version) WithLowestPrerelease() Version {
+	c := v.Clone()
+	c.Pre = v.LowestPrerelease().Pre
+	return c
+}
+
+// ReleaseChannel returns the release channel of v, which is the first
+// prerelease identifier if it is alphanumeric, e.g. "beta" for 1.2.0-beta.3.
+// It returns an empty string for a stable release, and also for a
+// prerelease starting with a numeric identifier like 1.0.0-1, which has no
+// named channel.
+func (v Version) ReleaseChannel() string {
+	if len(v.Pre) == 0 || v.Pre[0].IsNum {
+		return ""
+	}
+	return v.Pre[0].VersionStr
+}
+
 // Version to string
 func (v Version) String() string {
-	b := make([]byte, 0, 5)
+	b := v.appendNoBuild(make([]byte, 0, 5))
+
+	if len(v.Build) > 0 {
+		b = append(b, '+')
+		b = append(b, v.Build[0]...)
+
+		for _, build := range v.Build[1:] {
+			b = append(b, '.')
+			b = append(b, build...)
+		}
+	}
+
+	return string(b)
+}
+
+// StringNoBuild returns the string of v without build metadata, e.g.
+// "1.2.3-rc.1" for 1.2.3-rc.1+build.5. The prerelease is kept. It is meant
+// for contexts which do not allow a "+", like npm dist-tags.
+func (v Version) StringNoBuild() string {
+	return string(v.appendNoBuild(make([]byte, 0, 5)))
+}
+
+// Key returns a string identifying v for use as a map key. Versions which
+// are Equals have the same Key, so build metadata is left out like in
+// StringNoBuild. Use CanonicalizeBuild().String() to tell versions apart by
+// their build metadata as well.
+func (v Version) Key() string {
+	return v.StringNoBuild()
+}
+
+// appendNoBuild appends the version core and prerelease of v to b.
+func (v Version) appendNoBuild(b []byte) []byte {
 	b = strconv.AppendUint(b, v.Major, 10)
 	b = append(b, '.')
 	b = strconv.AppendUint(b, v.Minor, 10)
@@ -47,17 +189,27 @@ func (v Version) String() string {
 			b = append(b, pre.String()...)
 		}
 	}
+	return b
+}
 
-	if len(v.Build) > 0 {
-		b = append(b, '+')
-		b = append(b, v.Build[0]...)
+// isZero checks if v is the zero Version.
+func (v Version) isZero() bool {
+	return v.Major == 0 && v.Minor == 0 && v.Patch == 0 && len(v.Pre) == 0 && len(v.Build) == 0
+}
 
-		for _, build := range v.Build[1:] {
-			b = append(b, '.')
-			b = append(b, build...)
-		}
+// Compact returns the version string with trailing zero components omitted,
+// e.g. "1.2" for 1.2.0 and "1" for 1.0.0. Versions with prerelease or build
+// meta data are returned in full.
+func (v Version) Compact() string {
+	if len(v.Pre) > 0 || len(v.Build) > 0 || v.Patch != 0 {
+		return v.String()
+	}
+	b := make([]byte, 0, 5)
+	b = strconv.AppendUint(b, v.Major, 10)
+	if v.Minor != 0 {
+		b = append(b, '.')
+		b = strconv.AppendUint(b, v.Minor, 10)
 	}
-
 	return string(b)
 }
 
@@ -71,6 +223,20 @@ func (v Version) EQ(o Version) bool {
 	return (v.Compare(o) == 0)
 }
 
+// EqualCore checks if v and o have the same major, minor and patch version,
+// ignoring prerelease and build meta data.
+func (v Version) EqualCore(o Version) bool {
+	return v.Major == o.Major && v.Minor == o.Minor && v.Patch == o.Patch
+}
+
+// SamePatchLine checks if v and o have the same major and minor version, so
+// they only differ in patch version, prerelease or build meta data. Unlike
+// EqualCore, which requires the same patch version, 1.2.3 and 1.2.9 are on
+// the same patch line.
+func (v Version) SamePatchLine(o Version) bool {
+	return v.Major == o.Major && v.Minor == o.Minor
+}
+
 // NE checks if v is not equal to o.
 func (v Version) NE(o Version) bool {
 	return (v.Compare(o) != 0)
@@ -130,18 +296,31 @@ func (v Version) Compare(o Version) int {
 		return -1
 	}
 
+	return ComparePrerelease(v.Pre, o.Pre)
+}
+
+// ComparePrerelease compares the prerelease versions a and b by the
+// precedence rules of SemVer: identifiers are compared in turn, numeric ones
+// by value, others lexically in ASCII order, and numeric ones sort lower.
+// If all identifiers are equal, the longer list has higher precedence. An
+// empty list means no prerelease, which has higher precedence than any
+// prerelease.
+// -1 == a is less than b
+// 0 == a is equal to b
+// 1 == a is greater than b
+func ComparePrerelease(a, b []PRVersion) int {
 	// Quick comparison if a version has no prerelease versions
-	if len(v.Pre) == 0 && len(o.Pre) == 0 {
+	if len(a) == 0 && len(b) == 0 {
 		return 0
-	} else if len(v.Pre) == 0 && len(o.Pre) > 0 {
+	} else if len(a) == 0 && len(b) > 0 {
 		return 1
-	} else if len(v.Pre) > 0 && len(o.Pre) == 0 {
+	} else if len(a) > 0 && len(b) == 0 {
 		return -1
 	}
 
 	i := 0
-	for ; i < len(v.Pre) && i < len(o.Pre); i++ {
-		if comp := v.Pre[i].Compare(o.Pre[i]); comp == 0 {
+	for ; i < len(a) && i < len(b); i++ {
+		if comp := a[i].Compare(b[i]); comp == 0 {
 			continue
 		} else if comp == 1 {
 			return 1
@@ -151,9 +330,9 @@ func (v Version) Compare(o Version) int {
 	}
 
 	// If all pr versions are the equal but one has further prversion, this one greater
-	if i == len(v.Pre) && i == len(o.Pre) {
+	if i == len(a) && i == len(b) {
 		return 0
-	} else if i == len(v.Pre) && i < len(o.Pre) {
+	} else if i == len(a) && i < len(b) {
 		return -1
 	} else {
 		return 1
@@ -161,6 +340,81 @@ func (v Version) Compare(o Version) int {
 
 }
 
+// CompareBuild compares v and o like Compare, but breaks ties by their
+// build meta data, which gives a total order of versions. Build identifiers
+// are compared in turn like prerelease identifiers: numeric ones by value,
+// others as strings, and numeric ones sort first. A version with fewer
+// identifiers sorts first if all others are equal.
+func (v Version) CompareBuild(o Version) int {
+	if c := v.Compare(o); c != 0 {
+		return c
+	}
+	for i := 0; i < len(v.Build) && i < len(o.Build); i++ {
+		if c := compareBuildIdentifier(v.Build[i], o.Build[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.Build) < len(o.Build):
+		return -1
+	case len(v.Build) > len(o.Build):
+		return 1
+	}
+	return 0
+}
+
+// compareBuildIdentifier compares two build identifiers. Numeric ones may
+// contain leading zeroes, equal numbers with fewer of them sort first.
+func compareBuildIdentifier(a, b string) int {
+	aNum, bNum := containsOnly(a, numbers), containsOnly(b, numbers)
+	switch {
+	case aNum && !bNum:
+		return -1
+	case !aNum && bNum:
+		return 1
+	case aNum && bNum:
+		ta, tb := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(ta) != len(tb) {
+			if len(ta) < len(tb) {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(ta, tb); c != 0 {
+			return c
+		}
+		// fewer leading zeroes first
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// NewnessScore computes major*weights[0] + minor*weights[1] + patch*weights[2]
+// for ranking versions. The arithmetic saturates at the maximum uint64
+// instead of overflowing. Prereleases score one less than their release, so
+// with a patch weight of at least 2 they rank strictly between the previous
+// patch and their release. With a patch weight of 1, 1.2.3-rc ties with 1.2.2.
+func NewnessScore(v Version, weights [3]uint64) uint64 {
+	var score uint64
+	for i, n := range []uint64{v.Major, v.Minor, v.Patch} {
+		hi, lo := bits.Mul64(n, weights[i])
+		sum, carry := bits.Add64(score, lo, 0)
+		if hi != 0 || carry != 0 {
+			return math.MaxUint64
+		}
+		score = sum
+	}
+	if len(v.Pre) > 0 && score > 0 {
+		score--
+	}
+	return score
+}
+
 // IncrementPatch increments the patch version
 func (v *Version) IncrementPatch() error {
 	if v.Major == 0 {
@@ -191,24 +445,123 @@ func (v *Version) IncrementMajor() error {
 	return nil
 }
 
+// IncrementPatchBy increments the patch version by n, like calling
+// IncrementPatch n times. An error is returned if the patch version would
+// overflow.
+func (v *Version) IncrementPatchBy(n uint64) error {
+	if v.Major == 0 || v.Patch > math.MaxUint64-n {
+		return fmt.Errorf("Patch version can not be incremented by %d for %q", n, v.String())
+	}
+	v.Patch += n
+	return nil
+}
+
+// IncrementMinorBy increments the minor version by n, like calling
+// IncrementMinor n times. An error is returned if the minor version would
+// overflow.
+func (v *Version) IncrementMinorBy(n uint64) error {
+	if v.Major == 0 || v.Minor > math.MaxUint64-n {
+		return fmt.Errorf("Minor version can not be incremented by %d for %q", n, v.String())
+	}
+	if n == 0 {
+		return nil
+	}
+	v.Minor += n
+	v.Patch = 0
+	return nil
+}
+
+// IncrementMajorBy increments the major version by n, like calling
+// IncrementMajor n times. An error is returned if the major version would
+// overflow.
+func (v *Version) IncrementMajorBy(n uint64) error {
+	if v.Major == 0 || v.Major > math.MaxUint64-n {
+		return fmt.Errorf("Major version can not be incremented by %d for %q", n, v.String())
+	}
+	if n == 0 {
+		return nil
+	}
+	v.Major += n
+	v.Minor = 0
+	v.Patch = 0
+	return nil
+}
+
+// IncrementPrerelease increments the prerelease version. If the last
+// prerelease identifier is numeric it is incremented, e.g. 1.2.0-rc.1
+// becomes 1.2.0-rc.2, otherwise the identifier 1 is appended, e.g. 1.2.0-rc
+// becomes 1.2.0-rc.1. Versions without prerelease can not be incremented.
+func (v *Version) IncrementPrerelease() error {
+	if len(v.Pre) == 0 {
+		return fmt.Errorf("Prerelease version can not be incremented for %q", v.String())
+	}
+	pre := make([]PRVersion, len(v.Pre), len(v.Pre)+1)
+	copy(pre, v.Pre)
+	if last := &pre[len(pre)-1]; last.IsNum {
+		if last.VersionNum == math.MaxUint64 {
+			return fmt.Errorf("Prerelease version can not be incremented for %q", v.String())
+		}
+		last.VersionNum++
+	} else {
+		pre = append(pre, PRVersion{VersionNum: 1, IsNum: true})
+	}
+	v.Pre = pre
+	return nil
+}
+
+// FinalizeOrBump moves v to its next release like node-semver's inc with
+// "patch". A prerelease is finalized to the release of the same core, e.g.
+// 1.2.3-rc.1 becomes 1.2.3, while a release gets the next patch version,
+// e.g. 1.2.3 becomes 1.2.4, also for major version 0. Build meta data is
+// removed in both cases. This differs from IncrementPatch, which always
+// increments the patch version, keeps the prerelease and refuses major
+// version 0.
+func (v *Version) FinalizeOrBump() error {
+	if len(v.Pre) == 0 {
+		v.Patch++
+	}
+	v.Pre = nil
+	v.Build = nil
+	return nil
+}
+
+// SetPrerelease validates the given prerelease identifiers and replaces the
+// prerelease versions of v with them. Calling it without identifiers removes
+// the prerelease versions. On error v is left unchanged.
+func (v *Version) SetPrerelease(ids ...string) error {
+	var pre []PRVersion
+	for _, id := range ids {
+		p, err := NewPRVersion(id)
+		if err != nil {
+			return err
+		}
+		pre = append(pre, p)
+	}
+	v.Pre = pre
+	return nil
+}
+
 // Validate validates v and returns error in case
 func (v Version) Validate() error {
 	// Major, Minor, Patch already validated using uint64
 
-	for _, pre := range v.Pre {
+	for i, pre := range v.Pre {
 		if !pre.IsNum { //Numeric prerelease versions already uint64
 			if len(pre.VersionStr) == 0 {
-				return fmt.Errorf("Prerelease can not be empty %q", pre.VersionStr)
+				return fmt.Errorf("Prerelease identifier %d can not be empty", i)
 			}
 			if !containsOnly(pre.VersionStr, alphanum) {
 				return fmt.Errorf("Invalid character(s) found in prerelease %q", pre.VersionStr)
 			}
+			if containsOnly(pre.VersionStr, numbers) && hasLeadingZeroes(pre.VersionStr) {
+				return fmt.Errorf("Numeric PreRelease version must not contain leading zeroes %q", pre.VersionStr)
+			}
 		}
 	}
 
-	for _, build := range v.Build {
+	for i, build := range v.Build {
 		if len(build) == 0 {
-			return fmt.Errorf("Build meta data can not be empty %q", build)
+			return fmt.Errorf("Build meta data identifier %d can not be empty", i)
 		}
 		if !containsOnly(build, alphanum) {
 			return fmt.Errorf("Invalid character(s) found in build meta data %q", build)
@@ -218,6 +571,19 @@ func (v Version) Validate() error {
 	return nil
 }
 
+// StrictlyCompliant checks if s is a SemVer 2.0.0 version string in its
+// canonical form, which holds if it parses without any tolerant fixups.
+// It rejects the "v" prefix Parse accepts. There is no such check for a
+// Version, which does not record how it was written: a Version parsed
+// tolerantly from "v1.2" equals one parsed from "1.2.0".
+func StrictlyCompliant(s string) bool {
+	if strings.HasPrefix(s, "v") {
+		return false
+	}
+	v, err := Parse(s)
+	return err == nil && v.String() == s
+}
+
 // New is an alias for Parse and returns a pointer, parses version string and returns a validated Version or error
 func New(s string) (vp *Version, err error) {
 	v, err := Parse(s)
@@ -233,7 +599,8 @@ func Make(s string) (Version, error) {
 // ParseTolerant allows for certain version specifications that do not strictly adhere to semver
 // specs to be parsed by this library. It does so by normalizing versions before passing them to
 // Parse(). It currently trims spaces, removes a "v" prefix, adds a 0 patch number to versions
-// with only major and minor components specified, and removes leading 0s.
+// with only major and minor components specified, and removes leading 0s of the version numbers
+// and of numeric prerelease identifiers.
 func ParseTolerant(s string) (Version, error) {
 	s = strings.TrimSpace(s)
 	s = strings.TrimPrefix(s, "v")
@@ -242,10 +609,9 @@ func ParseTolerant(s string) (Version, error) {
 	parts := strings.SplitN(s, ".", 3)
 	// Remove leading zeros.
 	for i, p := range parts {
-		if len(p) > 1 {
-			parts[i] = strings.TrimPrefix(p, "0")
-		}
+		parts[i] = trimLeadingZeroes(p)
 	}
+	parts[len(parts)-1] = trimPrereleaseZeroes(parts[len(parts)-1])
 	// Fill up shortened versions.
 	if len(parts) < 3 {
 		if strings.ContainsAny(parts[len(parts)-1], "+-") {
@@ -260,7 +626,65 @@ func ParseTolerant(s string) (Version, error) {
 	return Parse(s)
 }
 
-// Parse parses version string and returns a validated Version or error
+// trimLeadingZeroes removes the leading zeros of the number s starts with,
+// keeping its last digit.
+func trimLeadingZeroes(s string) string {
+	i := 0
+	for i < len(s)-1 && s[i] == '0' && strings.IndexByte(numbers, s[i+1]) != -1 {
+		i++
+	}
+	return s[i:]
+}
+
+// trimPrereleaseZeroes removes the leading zeros of numeric prerelease
+// identifiers in the patch part s of a version string.
+func trimPrereleaseZeroes(s string) string {
+	start := strings.IndexByte(s, '-')
+	if start == -1 {
+		return s
+	}
+	end := strings.IndexByte(s, '+')
+	if end == -1 {
+		end = len(s)
+	} else if end < start {
+		// a hyphen in build meta data
+		return s
+	}
+	ids := strings.Split(s[start+1:end], ".")
+	for i, id := range ids {
+		if containsOnly(id, numbers) {
+			ids[i] = trimLeadingZeroes(id)
+		}
+	}
+	return s[:start+1] + strings.Join(ids, ".") + s[end:]
+}
+
+// Coerce extracts the first version looking part of s, like the coerce of
+// node-semver. It finds the first "X", "X.Y" or "X.Y.Z" of up to 16 digit
+// numbers in s, filling missing components with zero, so "myapp-v2.3.1-amd64"
+// becomes 2.3.1 and "release 7" becomes 7.0.0. A run of more than 16 digits
+// is skipped rather than coerced, so "v12345678901234567 1.2" becomes 1.2.0,
+// and a longer minor or patch number ends the version there. Prerelease and
+// build meta data are never extracted. It returns false if s contains no
+// number of at most 16 digits.
+func Coerce(s string) (Version, bool) {
+	match := rangeRegex["COERCE"].FindStringSubmatch(s)
+	if match == nil {
+		return Version{}, false
+	}
+	var parts [3]uint64
+	for i, m := range match[1:] {
+		if len(m) > 0 {
+			// at most 16 digits always fit
+			parts[i], _ = strconv.ParseUint(m, 10, 64)
+		}
+	}
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}, true
+}
+
+// Parse parses version string and returns a validated Version or error.
+// A single leading "v" is accepted, like for the versions of a range, so
+// "v1.2.3" parses as 1.2.3, while "vv1.2.3" and "V1.2.3" are invalid.
 func Parse(s string) (Version, error) {
 	if len(s) == 0 {
 		return Version{}, errors.New("Version string empty")
@@ -282,7 +706,7 @@ func Parse(s string) (Version, error) {
 	if hasLeadingZeroes(parts[0]) {
 		return Version{}, fmt.Errorf("Major number must not contain leading zeroes %q", parts[0])
 	}
-	major, err := strconv.ParseUint(parts[0], 10, 64)
+	major, err := parseNumber(parts[0], "major")
 	if err != nil {
 		return Version{}, err
 	}
@@ -294,7 +718,7 @@ func Parse(s string) (Version, error) {
 	if hasLeadingZeroes(parts[1]) {
 		return Version{}, fmt.Errorf("Minor number must not contain leading zeroes %q", parts[1])
 	}
-	minor, err := strconv.ParseUint(parts[1], 10, 64)
+	minor, err := parseNumber(parts[1], "minor")
 	if err != nil {
 		return Version{}, err
 	}
@@ -303,17 +727,10 @@ func Parse(s string) (Version, error) {
 	v.Major = major
 	v.Minor = minor
 
-	var build, prerelease []string
-	patchStr := parts[2]
-
-	if buildIndex := strings.IndexRune(patchStr, '+'); buildIndex != -1 {
-		build = strings.Split(patchStr[buildIndex+1:], ".")
-		patchStr = patchStr[:buildIndex]
-	}
-
-	if preIndex := strings.IndexRune(patchStr, '-'); preIndex != -1 {
-		prerelease = strings.Split(patchStr[preIndex+1:], ".")
-		patchStr = patchStr[:preIndex]
+	// Patch ends at the first prerelease or build meta data separator
+	patchStr, tail := parts[2], ""
+	if i := strings.IndexAny(patchStr, "-+"); i != -1 {
+		patchStr, tail = patchStr[:i], patchStr[i:]
 	}
 
 	if !containsOnly(patchStr, numbers) {
@@ -322,18 +739,37 @@ func Parse(s string) (Version, error) {
 	if hasLeadingZeroes(patchStr) {
 		return Version{}, fmt.Errorf("Patch number must not contain leading zeroes %q", patchStr)
 	}
-	patch, err := strconv.ParseUint(patchStr, 10, 64)
+	patch, err := parseNumber(patchStr, "patch")
 	if err != nil {
 		return Version{}, err
 	}
 
 	v.Patch = patch
 
+	if err := parsePreBuild(&v, tail); err != nil {
+		return Version{}, err
+	}
+	return v, nil
+}
+
+// parsePreBuild parses the prerelease and build meta data of a version
+// string into v. The tail is the part of the string following the patch
+// number, it is either empty or starts with "-" or "+".
+func parsePreBuild(v *Version, tail string) error {
+	var build, prerelease []string
+	if buildIndex := strings.IndexRune(tail, '+'); buildIndex != -1 {
+		build = strings.Split(tail[buildIndex+1:], ".")
+		tail = tail[:buildIndex]
+	}
+	if len(tail) > 0 {
+		prerelease = strings.Split(tail[1:], ".")
+	}
+
 	// Prerelease
 	for _, prstr := range prerelease {
 		parsedPR, err := NewPRVersion(prstr)
 		if err != nil {
-			return Version{}, err
+			return err
 		}
 		v.Pre = append(v.Pre, parsedPR)
 	}
@@ -341,14 +777,222 @@ func Parse(s string) (Version, error) {
 	// Build meta data
 	for _, str := range build {
 		if len(str) == 0 {
-			return Version{}, errors.New("Build meta data is empty")
+			return &ParseError{Kind: ErrEmptyComponent, Component: "build meta data"}
 		}
 		if !containsOnly(str, alphanum) {
-			return Version{}, fmt.Errorf("Invalid character(s) found in build meta data %q", str)
+			return fmt.Errorf("Invalid character(s) found in build meta data %q", str)
 		}
 		v.Build = append(v.Build, str)
 	}
+	return nil
+}
+
+// ParseBytes is like Parse, but parses a version from a byte slice. It is
+// the fast path for parsing many versions, e.g. lines read from a file:
+// versions without prerelease and build meta data are parsed without any
+// allocation, others without copying their major, minor and patch numbers.
+func ParseBytes(b []byte) (Version, error) {
+	if v, n, ok := parseCoreBytes(b); ok {
+		if n == len(b) {
+			return v, nil
+		}
+		if b[n] == '-' || b[n] == '+' {
+			if err := parsePreBuild(&v, string(b[n:])); err != nil {
+				return Version{}, err
+			}
+			return v, nil
+		}
+	}
+	// errors are reported by Parse
+	return Parse(string(b))
+}
+
+// parseCoreBytes parses a valid "MAJOR.MINOR.PATCH" at the start of b,
+// optionally prefixed with "v". It returns the parsed version and the index
+// following the patch number, or false if b does not start with a valid core.
+func parseCoreBytes(b []byte) (Version, int, bool) {
+	i := 0
+	if i < len(b) && b[i] == 'v' {
+		i++
+	}
+	var nums [3]uint64
+	for k := range nums {
+		if k > 0 {
+			if i >= len(b) || b[i] != '.' {
+				return Version{}, 0, false
+			}
+			i++
+		}
+		start := i
+		for ; i < len(b) && b[i] >= '0' && b[i] <= '9'; i++ {
+			d := uint64(b[i] - '0')
+			if nums[k] > (math.MaxUint64-d)/10 {
+				return Version{}, 0, false
+			}
+			nums[k] = nums[k]*10 + d
+		}
+		if i == start || (b[start] == '0' && i-start > 1) {
+			return Version{}, 0, false
+		}
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, i, true
+}
 
+// InList checks if v is equal to one of the versions in the comma separated
+// list, e.g. "1.2.3,1.2.4,2.0.0". An error is returned if any version of the
+// list can not be parsed.
+func InList(v Version, list string) (bool, error) {
+	found := false
+	for _, s := range strings.Split(list, ",") {
+		o, err := Parse(strings.TrimSpace(s))
+		if err != nil {
+			return false, fmt.Errorf("Invalid version %q in list: %s", s, err)
+		}
+		if v.EQ(o) {
+			found = true
+		}
+	}
+	return found, nil
+}
+
+// ParseList parses the versions in s separated by sep, e.g. "1.2.3, 1.2.4"
+// with the separator ",". Whitespace around the versions is ignored. The
+// error for an invalid version reports its index in the list.
+func ParseList(s, sep string) ([]Version, error) {
+	if len(sep) == 0 {
+		return nil, errors.New("Separator can not be empty")
+	}
+	parts := strings.Split(s, sep)
+	vs := make([]Version, 0, len(parts))
+	for i, part := range parts {
+		v, err := Parse(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid version %q at index %d: %s", part, i, err)
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// CompareStrings parses the versions a and b and compares them like
+// Version.Compare. An error is returned if either can not be parsed.
+func CompareStrings(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid version %q: %s", a, err)
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid version %q: %s", b, err)
+	}
+	return va.Compare(vb), nil
+}
+
+// GreaterThanStrings checks if the version a is greater than the version b.
+// An error is returned if either can not be parsed.
+func GreaterThanStrings(a, b string) (bool, error) {
+	c, err := CompareStrings(a, b)
+	return c > 0, err
+}
+
+// ParseOptions configures optional behavior of ParseWithOptions.
+type ParseOptions struct {
+	// StablePrereleases lists prerelease identifiers which mark a stable
+	// release, e.g. DefaultStablePrereleases. A version whose prerelease
+	// consists of just one of them is parsed without prerelease, so
+	// "1.2.3-final" becomes "1.2.3". Identifiers are compared case
+	// insensitively.
+	StablePrereleases []string
+}
+
+// DefaultStablePrereleases are prerelease identifiers commonly used to tag
+// stable releases.
+var DefaultStablePrereleases = []string{"final", "release", "ga"}
+
+// ParseWithOptions is like Parse, with the optional behavior configured in
+// opts. The zero ParseOptions behave exactly like Parse.
+func ParseWithOptions(s string, opts ParseOptions) (Version, error) {
+	v, err := Parse(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if len(v.Pre) == 1 && !v.Pre[0].IsNum {
+		for _, id := range opts.StablePrereleases {
+			if strings.EqualFold(v.Pre[0].VersionStr, id) {
+				v.Pre = nil
+				break
+			}
+		}
+	}
+	return v, nil
+}
+
+// PreservedVersion is a Version together with the string it was parsed
+// from, see ParsePreserving.
+type PreservedVersion struct {
+	Version
+	// Original is the string the Version was parsed from.
+	Original string
+}
+
+// ParsePreserving parses a version like Parse, but keeps the input, so the
+// original formatting like a "v" prefix can be written back unchanged.
+// A Version itself can not hold its input without breaking positional
+// Version literals, so it is returned as a PreservedVersion.
+func ParsePreserving(s string) (PreservedVersion, error) {
+	v, err := Parse(s)
+	if err != nil {
+		return PreservedVersion{}, err
+	}
+	return PreservedVersion{Version: v, Original: s}, nil
+}
+
+// String returns the original input, unless the Version has been modified
+// since parsing, in which case the string of the Version is returned. Other
+// methods, like MarshalJSON, always use the Version.
+func (p PreservedVersion) String() string {
+	if v, err := Parse(p.Original); err == nil && v.String() == p.Version.String() {
+		return p.Original
+	}
+	return p.Version.String()
+}
+
+// ParseAnyPrefix parses a version behind one of the given prefixes, e.g.
+// "release/1.2.3" with the prefix "release/". The prefixes are tried in
+// order, followed by s itself, which may carry a "v" prefix like for Parse.
+// The first successfully parsed version is returned.
+func ParseAnyPrefix(s string, prefixes []string) (Version, error) {
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(s, prefix) {
+			continue
+		}
+		if v, err := Parse(s[len(prefix):]); err == nil {
+			return v, nil
+		}
+	}
+	if v, err := Parse(s); err == nil {
+		return v, nil
+	}
+	return Version{}, fmt.Errorf("Could not parse version %q with any of the prefixes %q", s, prefixes)
+}
+
+// ParseForChannel parses s like Parse and requires the version to be on the
+// given release channel, as returned by ReleaseChannel, e.g. "beta" for
+// 1.2.0-beta.3. The "stable" channel requires a version without prerelease.
+// A prerelease starting with a numeric identifier like 1.0.0-1 is on no
+// channel.
+func ParseForChannel(s, channel string) (Version, error) {
+	v, err := Parse(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if channel == "stable" {
+		if len(v.Pre) > 0 {
+			return Version{}, fmt.Errorf("Version %q is not on the stable channel", s)
+		}
+	} else if got := v.ReleaseChannel(); got == "" || got != channel {
+		return Version{}, fmt.Errorf("Version %q is not on the %q channel", s, channel)
+	}
 	return v, nil
 }
 
@@ -371,16 +1015,14 @@ type PRVersion struct {
 // NewPRVersion creates a new valid prerelease version
 func NewPRVersion(s string) (PRVersion, error) {
 	if len(s) == 0 {
-		return PRVersion{}, errors.New("Prerelease is empty")
+		return PRVersion{}, &ParseError{Kind: ErrEmptyComponent, Component: "prerelease"}
 	}
 	v := PRVersion{}
 	if containsOnly(s, numbers) {
 		if hasLeadingZeroes(s) {
 			return PRVersion{}, fmt.Errorf("Numeric PreRelease version must not contain leading zeroes %q", s)
 		}
-		num, err := strconv.ParseUint(s, 10, 64)
-
-		// Might never be hit, but just in case
+		num, err := parseNumber(s, "prerelease")
 		if err != nil {
 			return PRVersion{}, err
 		}
@@ -1,6 +1,8 @@
 package semver
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -35,6 +37,11 @@ var tolerantFormatTests = []formatTest{
 	{Version{1, 2, 3, nil, nil}, "	1.2.3 "},
 	{Version{1, 2, 3, nil, nil}, "01.02.03"},
 	{Version{0, 0, 3, nil, nil}, "00.0.03"},
+	{Version{1, 2, 3, nil, nil}, "001.002.003"},
+	{Version{1, 2, 0, []PRVersion{prstr("rc")}, nil}, "1.2.0-rc"},
+	{Version{1, 2, 3, []PRVersion{prstr("rc"), prnum(1)}, nil}, "1.2.3-rc.01"},
+	{Version{1, 2, 3, []PRVersion{prnum(0), prnum(10)}, []string{"007"}}, "01.02.03-00.010+007"},
+	{Version{1, 2, 3, []PRVersion{prstr("0a")}, []string{"b-01"}}, "1.2.3-0a+b-01"},
 	{Version{1, 2, 0, nil, nil}, "1.2"},
 	{Version{1, 0, 0, nil, nil}, "1"},
 }
@@ -47,6 +54,261 @@ func TestStringer(t *testing.T) {
 	}
 }
 
+func TestStringNoBuild(t *testing.T) {
+	tests := []struct {
+		v       string
+		str     string
+		noBuild string
+	}{
+		{"1.2.3-rc.1+build.5", "1.2.3-rc.1+build.5", "1.2.3-rc.1"},
+		{"1.2.3+build", "1.2.3+build", "1.2.3"},
+		{"1.2.3-alpha.1-b", "1.2.3-alpha.1-b", "1.2.3-alpha.1-b"},
+		{"1.2.3", "1.2.3", "1.2.3"},
+	}
+	for _, test := range tests {
+		v := MustParse(test.v)
+		if s := v.String(); s != test.str {
+			t.Errorf("String, expected %q but got %q", test.str, s)
+		}
+		if s := v.StringNoBuild(); s != test.noBuild {
+			t.Errorf("StringNoBuild, expected %q but got %q", test.noBuild, s)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"1.2.3", "v1.2.3", true},
+		{"1.2.3", "1.2.3+build.5", true},
+		{"1.2.3-rc.1+a", "1.2.3-rc.1+b", true},
+		{"1.2.3-rc.1", "1.2.3-rc.2", false},
+		{"1.2.3-rc.1", "1.2.3", false},
+		{"1.2.3", "1.2.4", false},
+	}
+	for _, test := range tests {
+		a, b := MustParse(test.a), MustParse(test.b)
+		if equal := a.Key() == b.Key(); equal != test.equal {
+			t.Errorf("Key of %q and %q, expected equal %t but got %q and %q", test.a, test.b, test.equal, a.Key(), b.Key())
+		}
+		if a.Equals(b) != test.equal {
+			t.Errorf("Key of %q and %q does not agree with Equals", test.a, test.b)
+		}
+	}
+
+	seen := make(map[string]int)
+	for _, s := range []string{"1.2.3", "1.2.3+build", "v1.2.3", "2.0.0-rc.1", "2.0.0-rc.1+x"} {
+		seen[MustParse(s).Key()]++
+	}
+	if len(seen) != 2 || seen["1.2.3"] != 3 || seen["2.0.0-rc.1"] != 2 {
+		t.Errorf("Unexpected keys %v", seen)
+	}
+}
+
+func TestClone(t *testing.T) {
+	v := MustParse("1.2.3-rc.1+build.5")
+	c := v.Clone()
+	c.Pre[0] = prstr("beta")
+	c.Build[1] = "6"
+	if s := v.String(); s != "1.2.3-rc.1+build.5" {
+		t.Errorf("Expected original to be unchanged, got %q", s)
+	}
+	if s := c.String(); s != "1.2.3-beta.1+build.6" {
+		t.Errorf("Expected clone %q, got %q", "1.2.3-beta.1+build.6", s)
+	}
+
+	// Replacing the prerelease of a plain copy does not touch the original
+	p := v
+	if err := p.SetPrerelease("rc", "2"); err != nil {
+		t.Fatalf("Unexpected error %q", err)
+	}
+	if err := p.IncrementPatch(); err != nil {
+		t.Fatalf("Unexpected error %q", err)
+	}
+	if s := v.String(); s != "1.2.3-rc.1+build.5" {
+		t.Errorf("Expected original to be unchanged, got %q", s)
+	}
+
+	if c := (Version{1, 2, 3, nil, nil}).Clone(); c.Pre != nil || c.Build != nil {
+		t.Errorf("Expected clone without prerelease and build, got %#v", c)
+	}
+}
+
+func TestCore(t *testing.T) {
+	v := MustParse("1.2.3-rc.1+build")
+	if c := v.Core(); c.String() != "1.2.3" {
+		t.Errorf("Expected core %q, got %q", "1.2.3", c)
+	}
+	if s := v.String(); s != "1.2.3-rc.1+build" {
+		t.Errorf("Expected original to be unchanged, got %q", s)
+	}
+}
+
+func TestWithoutBuildPrerelease(t *testing.T) {
+	v := MustParse("1.2.3-rc.1+build.5")
+	b := v.WithoutBuild()
+	if b.String() != "1.2.3-rc.1" {
+		t.Errorf("Expected %q, got %q", "1.2.3-rc.1", b)
+	}
+	p := v.WithoutPrerelease()
+	if p.String() != "1.2.3+build.5" {
+		t.Errorf("Expected %q, got %q", "1.2.3+build.5", p)
+	}
+	if c := v.WithoutBuild().WithoutPrerelease(); c.String() != "1.2.3" {
+		t.Errorf("Expected %q, got %q", "1.2.3", c)
+	}
+
+	// The copies do not share the remaining slice with the original
+	b.Pre[0] = prstr("beta")
+	p.Build[0] = "other"
+	if s := v.String(); s != "1.2.3-rc.1+build.5" {
+		t.Errorf("Expected original to be unchanged, got %q", s)
+	}
+}
+
+func TestWithLowestPrerelease(t *testing.T) {
+	tests := []struct {
+		v      string
+		expect string
+	}{
+		{"1.2.0", "1.2.0-0"},
+		{"1.2.0-rc.1", "1.2.0-0"},
+		{"1.2.0-0", "1.2.0-0"},
+		{"1.2.0+build", "1.2.0-0+build"},
+	}
+	for _, test := range tests {
+		l := MustParse(test.v).WithLowestPrerelease()
+		if s := l.String(); s != test.expect {
+			t.Errorf("Expected %q, got %q", test.expect, s)
+		}
+		if !l.Equals(MustParse("1.2.0-0")) {
+			t.Errorf("Expected %q to equal the parsed %q", l, "1.2.0-0")
+		}
+		for _, s := range []string{"1.2.0-alpha", "1.2.0-0.0", "1.2.0-1", "1.2.0"} {
+			if o := MustParse(s); !l.LT(o) {
+				t.Errorf("Expected %q to be less than %q", l, o)
+			}
+		}
+		if o := MustParse("1.1.9"); !l.GT(o) {
+			t.Errorf("Expected %q to be greater than %q", l, o)
+		}
+	}
+
+	v := MustParse("1.2.0-0")
+	if len(v.Pre) != 1 || !v.Pre[0].IsNum || v.Pre[0].VersionNum != 0 {
+		t.Errorf("Expected numeric prerelease 0, got %#v", v.Pre)
+	}
+}
+
+func TestReleaseChannel(t *testing.T) {
+	tests := []struct {
+		v       string
+		channel string
+	}{
+		{"1.0.0-rc.1", "rc"},
+		{"1.2.0-beta.3", "beta"},
+		{"1.2.0-alpha", "alpha"},
+		{"1.2.0-rc1.2+build", "rc1"},
+		{"1.2.0-0a.1", "0a"},
+		{"1.0.0", ""},
+		{"1.0.0+build", ""},
+		{"1.0.0-1", ""},
+		{"1.0.0-0.beta", ""},
+	}
+	for _, test := range tests {
+		if c := MustParse(test.v).ReleaseChannel(); c != test.channel {
+			t.Errorf("ReleaseChannel of %q, expected %q but got %q", test.v, test.channel, c)
+		}
+	}
+}
+
+func TestCanonicalizeBuild(t *testing.T) {
+	tests := []struct {
+		v      Version
+		expect string
+	}{
+		{Version{1, 2, 3, nil, []string{"b", "a"}}, "1.2.3+a.b"},
+		{Version{1, 2, 3, nil, []string{"a", "b"}}, "1.2.3+a.b"},
+		{Version{1, 2, 3, []PRVersion{prstr("rc"), prnum(1)}, []string{"sha", "10", "9"}}, "1.2.3-rc.1+10.9.sha"},
+		{Version{1, 2, 3, nil, nil}, "1.2.3"},
+	}
+	for _, test := range tests {
+		if s := test.v.CanonicalizeBuild().String(); s != test.expect {
+			t.Errorf("Expected %q, got %q", test.expect, s)
+		}
+	}
+
+	// The receiver keeps the order of its build metadata
+	v := MustParse("1.2.3+z.y")
+	v.CanonicalizeBuild()
+	if s := v.String(); s != "1.2.3+z.y" {
+		t.Errorf("Expected original to be unchanged, got %q", s)
+	}
+}
+
+func TestWithComponents(t *testing.T) {
+	v := MustParse("1.2.3-rc.1+build.5")
+	tests := []struct {
+		o      Version
+		expect string
+	}{
+		{v.WithMajor(4), "4.2.3-rc.1+build.5"},
+		{v.WithMinor(0), "1.0.3-rc.1+build.5"},
+		{v.WithPatch(0), "1.2.0-rc.1+build.5"},
+		{v.WithMajor(2).WithMinor(7).WithPatch(9), "2.7.9-rc.1+build.5"},
+		{v.WithPatch(3), "1.2.3-rc.1+build.5"},
+	}
+	for _, test := range tests {
+		if s := test.o.String(); s != test.expect {
+			t.Errorf("Expected %q, got %q", test.expect, s)
+		}
+	}
+
+	// The receiver is not modified and the copy does not share slices
+	o := v.WithPatch(0)
+	o.Pre[0] = prstr("beta")
+	o.Build[0] = "other"
+	if s := v.String(); s != "1.2.3-rc.1+build.5" {
+		t.Errorf("Expected original to be unchanged, got %q", s)
+	}
+}
+
+func TestLowestPrerelease(t *testing.T) {
+	v := MustParse("1.2.3-rc.1+build")
+	l := v.LowestPrerelease()
+	if l.String() != "1.2.3-0" {
+		t.Errorf("Expected %q, got %q", "1.2.3-0", l)
+	}
+	for _, s := range []string{"1.2.3-alpha", "1.2.3-0.0", "1.2.3-1", "1.2.3"} {
+		if o := MustParse(s); !l.LT(o) {
+			t.Errorf("Expected %q to be less than %q", l, o)
+		}
+	}
+	if o := MustParse("1.2.2"); !l.GT(o) {
+		t.Errorf("Expected %q to be greater than %q", l, o)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []formatTest{
+		{Version{1, 2, 3, nil, nil}, "1.2.3"},
+		{Version{1, 2, 0, nil, nil}, "1.2"},
+		{Version{1, 0, 0, nil, nil}, "1"},
+		{Version{1, 0, 3, nil, nil}, "1.0.3"},
+		{Version{0, 0, 0, nil, nil}, "0"},
+		{Version{0, 1, 0, nil, nil}, "0.1"},
+		{Version{1, 0, 0, []PRVersion{prstr("rc")}, nil}, "1.0.0-rc"},
+		{Version{1, 2, 0, nil, []string{"build"}}, "1.2.0+build"},
+	}
+	for _, test := range tests {
+		if res := test.v.Compact(); res != test.result {
+			t.Errorf("Compact, expected %q but got %q", test.result, res)
+		}
+	}
+}
+
 func TestParse(t *testing.T) {
 	for _, test := range formatTests {
 		if v, err := Parse(test.result); err != nil {
@@ -71,6 +333,467 @@ func TestParseTolerant(t *testing.T) {
 	}
 }
 
+func TestInList(t *testing.T) {
+	tests := []struct {
+		v     string
+		list  string
+		found bool
+		err   bool
+	}{
+		{"1.2.4", "1.2.3,1.2.4,2.0.0", true, false},
+		{"1.2.4", "1.2.3, 1.2.4 , 2.0.0", true, false},
+		{"2.0.0+build", "1.2.3,1.2.4,2.0.0", true, false},
+		{"1.2.5", "1.2.3,1.2.4,2.0.0", false, false},
+		{"2.0.0-rc.1", "1.2.3,1.2.4,2.0.0", false, false},
+		{"1.2.3", "1.2.3,,2.0.0", false, true},
+		{"1.2.3", "1.2.3,1.2,2.0.0", false, true},
+		{"1.2.3", "", false, true},
+	}
+	for _, test := range tests {
+		found, err := InList(MustParse(test.v), test.list)
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected error for list %q, got none", test.list)
+			}
+		} else if err != nil {
+			t.Errorf("Unexpected error for list %q: %q", test.list, err)
+		} else if found != test.found {
+			t.Errorf("InList %q in %q, expected %t but got %t", test.v, test.list, test.found, found)
+		}
+	}
+}
+
+func TestComparePrerelease(t *testing.T) {
+	// canonical ordering of the SemVer spec
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if c := ComparePrerelease(MustParse(a).Pre, MustParse(b).Pre); c != expected {
+				t.Errorf("Comparing prerelease of %q and %q, expected %d but got %d", a, b, expected, c)
+			}
+		}
+	}
+
+	tests := []struct {
+		a, b []PRVersion
+		c    int
+	}{
+		// numeric identifiers sort lower than alphanumeric ones
+		{[]PRVersion{prnum(999)}, []PRVersion{prstr("a")}, -1},
+		{[]PRVersion{prstr("1a")}, []PRVersion{prnum(2)}, 1},
+		// numeric identifiers compare by value, alphanumeric ones in ASCII order
+		{[]PRVersion{prnum(9)}, []PRVersion{prnum(10)}, -1},
+		{[]PRVersion{prstr("B")}, []PRVersion{prstr("a")}, -1},
+		{[]PRVersion{prstr("alpha-2")}, []PRVersion{prstr("alpha-10")}, 1},
+		{nil, []PRVersion{}, 0},
+		{nil, []PRVersion{prnum(0)}, 1},
+	}
+	for _, test := range tests {
+		if c := ComparePrerelease(test.a, test.b); c != test.c {
+			t.Errorf("Comparing prerelease %v and %v, expected %d but got %d", test.a, test.b, test.c, c)
+		}
+	}
+}
+
+func TestEqualCore(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"1.2.3-rc.1", "1.2.3", true},
+		{"1.2.3-rc.1", "1.2.3-beta+build", true},
+		{"1.2.3+build", "1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"1.2.3", "1.3.3", false},
+		{"1.2.3", "2.2.3", false},
+	}
+	for _, test := range tests {
+		if equal := MustParse(test.a).EqualCore(MustParse(test.b)); equal != test.equal {
+			t.Errorf("EqualCore %q and %q, expected %t but got %t", test.a, test.b, test.equal, equal)
+		}
+	}
+}
+
+func TestSamePatchLine(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"1.2.3", "1.2.9", true},
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3-rc.1", "1.2.0+build", true},
+		{"1.2.3", "1.3.0", false},
+		{"1.2.3", "2.2.3", false},
+		{"0.2.3", "0.3.3", false},
+	}
+	for _, test := range tests {
+		if same := MustParse(test.a).SamePatchLine(MustParse(test.b)); same != test.same {
+			t.Errorf("SamePatchLine %q and %q, expected %t but got %t", test.a, test.b, test.same, same)
+		}
+	}
+}
+
+func TestCompareBuild(t *testing.T) {
+	tests := []struct {
+		a, b string
+		c    int
+	}{
+		{"1.2.3+a", "1.2.3+a", 0},
+		{"1.2.3", "1.2.3+a", -1},
+		{"1.2.3+a", "1.2.3+b", -1},
+		{"1.2.3+2", "1.2.3+10", -1},
+		{"1.2.3+7", "1.2.3+007", -1},
+		{"1.2.3+10", "1.2.3+a", -1},
+		{"1.2.3+a.1", "1.2.3+a", 1},
+		{"1.2.3+b", "1.2.4+a", -1},
+		{"1.2.3-rc+z", "1.2.3+a", -1},
+	}
+	for _, test := range tests {
+		a, b := MustParse(test.a), MustParse(test.b)
+		if c := a.CompareBuild(b); c != test.c {
+			t.Errorf("Comparing %q and %q with build, expected %d but got %d", test.a, test.b, test.c, c)
+		}
+		if c := b.CompareBuild(a); c != -test.c {
+			t.Errorf("Comparing %q and %q with build, expected %d but got %d", test.b, test.a, -test.c, c)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		s   string
+		sep string
+		o   []string
+	}{
+		{"1.2.3", ",", []string{"1.2.3"}},
+		{"1.2.3,1.2.4-rc.1, 2.0.0+build ", ",", []string{"1.2.3", "1.2.4-rc.1", "2.0.0+build"}},
+		{"1.2.3 | v2.0.0", "|", []string{"1.2.3", "2.0.0"}},
+		{"1.2.3 1.2.4", " ", []string{"1.2.3", "1.2.4"}},
+	}
+	for _, test := range tests {
+		vs, err := ParseList(test.s, test.sep)
+		if err != nil {
+			t.Errorf("Parsing list %q, unexpected error %q", test.s, err)
+			continue
+		}
+		var o []string
+		for _, v := range vs {
+			o = append(o, v.String())
+		}
+		if !reflect.DeepEqual(o, test.o) {
+			t.Errorf("Parsing list %q, expected %q but got %q", test.s, test.o, o)
+		}
+	}
+
+	_, err := ParseList("1.2.3,1.2,2.0.0", ",")
+	if err == nil || !strings.Contains(err.Error(), "index 1") {
+		t.Errorf("Expected error for index 1, got %v", err)
+	}
+	for _, s := range []string{"", "1.2.3,", "1.2.3,,1.2.4"} {
+		if _, err := ParseList(s, ","); err == nil {
+			t.Errorf("Parsing list %q, expected error but got none", s)
+		}
+	}
+	if _, err := ParseList("1.2.3", ""); err == nil {
+		t.Errorf("Expected error for empty separator, got none")
+	}
+}
+
+func TestCompareStrings(t *testing.T) {
+	tests := []struct {
+		a, b string
+		c    int
+		err  bool
+	}{
+		{"1.2.3", "1.2.3", 0, false},
+		{"1.2.3", "1.2.4", -1, false},
+		{"2.0.0", "1.9.9", 1, false},
+		{"1.0.0-rc.1", "1.0.0", -1, false},
+		{"v1.0.0", "1.0.0+build", 0, false},
+		{"1.2", "1.2.0", 0, true},
+		{"1.2.0", "foo", 0, true},
+		{"", "", 0, true},
+	}
+	for _, test := range tests {
+		c, err := CompareStrings(test.a, test.b)
+		gt, gtErr := GreaterThanStrings(test.a, test.b)
+		if test.err {
+			if err == nil || gtErr == nil {
+				t.Errorf("Expected error comparing %q and %q, got none", test.a, test.b)
+			}
+			continue
+		}
+		if err != nil || gtErr != nil {
+			t.Errorf("Unexpected error comparing %q and %q: %q, %q", test.a, test.b, err, gtErr)
+		} else if c != test.c || gt != (test.c > 0) {
+			t.Errorf("Comparing %q and %q, expected %d but got %d, %t", test.a, test.b, test.c, c, gt)
+		}
+	}
+}
+
+func TestParseWithOptionsStablePrereleases(t *testing.T) {
+	opts := ParseOptions{StablePrereleases: DefaultStablePrereleases}
+	tests := []struct {
+		s        string
+		opts     ParseOptions
+		expected string
+	}{
+		{"1.2.3-final", opts, "1.2.3"},
+		{"1.2.3-release+build", opts, "1.2.3+build"},
+		{"1.2.3-GA", opts, "1.2.3"},
+		{"1.2.3-final.1", opts, "1.2.3-final.1"},
+		{"1.2.3-rc.1", opts, "1.2.3-rc.1"},
+		{"1.2.3", opts, "1.2.3"},
+		{"1.2.3-final", ParseOptions{}, "1.2.3-final"},
+		{"1.2.3-stable", ParseOptions{StablePrereleases: []string{"stable"}}, "1.2.3"},
+	}
+	for _, test := range tests {
+		v, err := ParseWithOptions(test.s, test.opts)
+		if err != nil {
+			t.Errorf("Error parsing %q: %q", test.s, err)
+		} else if v.String() != test.expected {
+			t.Errorf("Parsing %q, expected %q but got %q", test.s, test.expected, v)
+		}
+	}
+	if v, _ := Parse("1.2.3-final"); len(v.Pre) != 1 {
+		t.Errorf("Expected Parse to keep the prerelease of %q, got %q", "1.2.3-final", v)
+	}
+	if _, err := ParseWithOptions("1.2", opts); err == nil {
+		t.Errorf("Expected error parsing %q, got none", "1.2")
+	}
+}
+
+func TestParseVPrefixConsistency(t *testing.T) {
+	tests := []struct {
+		s  string
+		ok bool
+	}{
+		{"v1.2.3", true},
+		{"v1.2.3-rc.1+build", true},
+		{"1.2.3", true},
+		{"vv1.2.3", false},
+		{"V1.2.3", false},
+		{"v 1.2.3", false},
+	}
+	for _, test := range tests {
+		v, err := Parse(test.s)
+		if (err == nil) != test.ok {
+			t.Errorf("Parse %q, expected ok %t but got error %v", test.s, test.ok, err)
+		}
+		r, rerr := ParseRange(test.s)
+		if (rerr == nil) != test.ok {
+			t.Errorf("ParseRange %q, expected ok %t but got error %v", test.s, test.ok, rerr)
+		}
+		if err == nil && rerr == nil && !r(v) {
+			t.Errorf("Expected range %q to match version %q", test.s, v)
+		}
+	}
+}
+
+func TestBuildLeadingZeroes(t *testing.T) {
+	tests := []struct {
+		s     string
+		valid bool
+	}{
+		{"1.2.3+001", true},
+		{"1.2.3+01", true},
+		{"1.2.3+exp.sha.05114f85", true},
+		{"1.2.3-rc.1+0.00.007", true},
+		{"1.2.3-01", false},
+		{"1.2.3-rc.01", false},
+		{"1.2.3-01+01", false},
+	}
+	for _, test := range tests {
+		v, err := Parse(test.s)
+		if (err == nil) != test.valid {
+			t.Errorf("Parse %q, expected valid %t but got error %v", test.s, test.valid, err)
+			continue
+		}
+		if _, err := ParseBytes([]byte(test.s)); (err == nil) != test.valid {
+			t.Errorf("ParseBytes %q, expected valid %t but got error %v", test.s, test.valid, err)
+		}
+		if !test.valid {
+			continue
+		}
+		if err := v.Validate(); err != nil {
+			t.Errorf("Validate %q, unexpected error %q", test.s, err)
+		}
+		if v.String() != test.s {
+			t.Errorf("Expected %q to be kept as written, got %q", test.s, v)
+		}
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v1.2.3", "1.2.3"},
+		{"myapp-v2.3.1-amd64", "2.3.1"},
+		{"release/1.2", "1.2.0"},
+		{"release 7", "7.0.0"},
+		{"1.2.3.4", "1.2.3"},
+		{"02.003.4-rc.1", "2.3.4"},
+		{"node:18-alpine", "18.0.0"},
+		{"build-1234567890123456789-x 4.5.6", "4.5.6"},
+		{"latest", ""},
+		{"", ""},
+		{"v.x.y", ""},
+	}
+	for _, test := range tests {
+		v, ok := Coerce(test.s)
+		if test.expected == "" {
+			if ok {
+				t.Errorf("Coerce %q, expected no version but got %q", test.s, v)
+			}
+		} else if !ok {
+			t.Errorf("Coerce %q, expected %q but got no version", test.s, test.expected)
+		} else if v.String() != test.expected {
+			t.Errorf("Coerce %q, expected %q but got %q", test.s, test.expected, v)
+		}
+	}
+}
+
+func TestParsePreserving(t *testing.T) {
+	for _, s := range []string{"v1.2.3+Build.01", "1.2.3", "v0.0.1-rc.1", "1.2.3-alpha+001"} {
+		p, err := ParsePreserving(s)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", s, err)
+			continue
+		}
+		if p.String() != s {
+			t.Errorf("Expected exact round-trip of %q, got %q", s, p)
+		}
+		if v := MustParse(s); !p.Equals(v) || p.Version.String() != v.String() {
+			t.Errorf("Expected version %q, got %q", v, p.Version)
+		}
+	}
+
+	// a modified version is no longer written as the original
+	p, _ := ParsePreserving("v1.2.3+Build.01")
+	if err := p.IncrementPatch(); err != nil {
+		t.Fatal(err)
+	}
+	if s := p.String(); s != "1.2.4+Build.01" {
+		t.Errorf("Expected %q after modification, got %q", "1.2.4+Build.01", s)
+	}
+
+	if _, err := ParsePreserving("v1.2"); err == nil {
+		t.Error("Expected error for invalid version")
+	}
+}
+
+func TestParseAnyPrefix(t *testing.T) {
+	prefixes := []string{"release/", "app-", "app-v"}
+	tests := []struct {
+		s string
+		o string
+	}{
+		{"release/1.2.3", "1.2.3"},
+		{"release/v1.2.3", "1.2.3"},
+		{"app-1.2.3-rc.1", "1.2.3-rc.1"},
+		{"app-v1.2.3", "1.2.3"},
+		{"v1.2.3", "1.2.3"},
+		{"1.2.3+build", "1.2.3+build"},
+		{"lib-1.2.3", ""},
+		{"release/1.2", ""},
+		{"release/", ""},
+		{"", ""},
+	}
+	for _, test := range tests {
+		v, err := ParseAnyPrefix(test.s, prefixes)
+		if test.o == "" {
+			if err == nil {
+				t.Errorf("Parsing %q with prefixes, expected error but got %q", test.s, v)
+			}
+		} else if err != nil {
+			t.Errorf("Parsing %q with prefixes, unexpected error %q", test.s, err)
+		} else if v.String() != test.o {
+			t.Errorf("Parsing %q with prefixes, expected %q but got %q", test.s, test.o, v)
+		}
+	}
+}
+
+func TestParseForChannel(t *testing.T) {
+	tests := []struct {
+		s       string
+		channel string
+		err     bool
+	}{
+		{"1.2.0-beta.3", "beta", false},
+		{"1.2.0-rc", "rc", false},
+		{"1.2.0", "stable", false},
+		{"1.2.0+build", "stable", false},
+		{"1.2.0-beta.3", "rc", true},
+		{"1.2.0-beta.3", "stable", true},
+		{"1.2.0", "beta", true},
+		{"1.2.0-rcx", "rc", true},
+		{"1.2", "stable", true},
+		{"1.0.0-1", "1", true},
+		{"1.0.0-1", "stable", true},
+		{"1.0.0-1", "", true},
+		{"1.0.0-1.beta", "1", true},
+		{"1.0.0", "", true},
+	}
+	for _, test := range tests {
+		v, err := ParseForChannel(test.s, test.channel)
+		if test.err {
+			if err == nil {
+				t.Errorf("Parsing %q for channel %q, expected error but got %q", test.s, test.channel, v)
+			}
+		} else if err != nil {
+			t.Errorf("Parsing %q for channel %q, unexpected error %q", test.s, test.channel, err)
+		} else if v.String() != test.s {
+			t.Errorf("Parsing %q for channel %q, got %q", test.s, test.channel, v)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	var inputs []string
+	for _, test := range formatTests {
+		inputs = append(inputs, test.result)
+	}
+	for _, test := range tolerantFormatTests {
+		inputs = append(inputs, test.result)
+	}
+	for _, test := range wrongformatTests {
+		inputs = append(inputs, test.str)
+	}
+	inputs = append(inputs, "v1.2.3", "vv1.2.3", "1.2.3.4", "1.2.3+b-c", "1.2.3-", "1.2.3+", "18446744073709551615.0.0", "18446744073709551616.0.0")
+
+	for _, s := range inputs {
+		want, wantErr := Parse(s)
+		v, err := ParseBytes([]byte(s))
+		if (err == nil) != (wantErr == nil) || (err != nil && err.Error() != wantErr.Error()) {
+			t.Errorf("Parsing bytes %q, expected error %v but got %v", s, wantErr, err)
+		} else if !reflect.DeepEqual(v, want) {
+			t.Errorf("Parsing bytes %q, expected %#v but got %#v", s, want, v)
+		}
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() { ParseBytes([]byte("1.2.3")) }); allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
 func TestMustParse(t *testing.T) {
 	_ = MustParse("32.2.1-alpha")
 }
@@ -92,6 +815,83 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestParseLeadingZeroes(t *testing.T) {
+	tests := []struct {
+		s string
+		o string
+	}{
+		{"01.02.03", "1.2.3"},
+		{"1.2.3-rc.01", "1.2.3-rc.1"},
+		{"1.2.3-001", "1.2.3-1"},
+		{"1.2.03-rc.0+build.01", "1.2.3-rc.0+build.01"},
+	}
+	for _, tc := range tests {
+		if _, err := Parse(tc.s); err == nil {
+			t.Errorf("Expected strict parsing of %q to fail", tc.s)
+		}
+		if v, err := ParseTolerant(tc.s); err != nil {
+			t.Errorf("Error parsing %q tolerantly: %s", tc.s, err)
+		} else if v.String() != tc.o {
+			t.Errorf("Parsing %q tolerantly, expected %q but got %q", tc.s, tc.o, v)
+		}
+	}
+}
+
+func TestStrictlyCompliant(t *testing.T) {
+	// tolerantly parsed input is not compliant, even if the result is
+	for _, s := range []string{"v1.2", "01.2.3", "1.2.3-rc.01"} {
+		v, err := ParseTolerant(s)
+		if err != nil {
+			t.Fatalf("Unexpected error %q", err)
+		}
+		if StrictlyCompliant(s) {
+			t.Errorf("Expected tolerantly parsed %q not to be compliant", s)
+		}
+		if !StrictlyCompliant(v.String()) {
+			t.Errorf("Expected %q to be compliant", v)
+		}
+	}
+
+	tests := []struct {
+		s string
+		b bool
+	}{
+		{"1.2.3", true},
+		{"1.2.3-rc.1+build.05", true},
+		{"0.0.0-0", true},
+		{"v1.2.3", false},
+		{" 1.2.3", false},
+		{"1.2", false},
+		{"01.2.3", false},
+		{"1.2.3-rc.01", false},
+		{"", false},
+	}
+	for _, tc := range tests {
+		if b := StrictlyCompliant(tc.s); b != tc.b {
+			t.Errorf("Invalid for case %q: Expected %t, got: %t", tc.s, tc.b, b)
+		}
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		v   Version
+		err string
+	}{
+		{Version{1, 2, 3, nil, []string{"a", "", "b"}}, "Build meta data identifier 1 can not be empty"},
+		{Version{1, 2, 3, nil, []string{"b_uild"}}, `Invalid character(s) found in build meta data "b_uild"`},
+		{Version{1, 2, 3, []PRVersion{prstr("")}, nil}, "Prerelease identifier 0 can not be empty"},
+		{Version{1, 2, 3, []PRVersion{prstr("rc"), prstr("01")}, nil}, `Numeric PreRelease version must not contain leading zeroes "01"`},
+	}
+	for _, test := range tests {
+		if err := test.v.Validate(); err == nil {
+			t.Errorf("Validating %q, expected error %q but got none", test.v, test.err)
+		} else if err.Error() != test.err {
+			t.Errorf("Validating %q, expected error %q but got %q", test.v, test.err, err)
+		}
+	}
+}
+
 type compareTest struct {
 	v1     Version
 	v2     Version
@@ -188,6 +988,13 @@ var wrongformatTests = []wrongformatTest{
 	// empty build meta data
 	{&Version{0, 0, 0, []PRVersion{prstr("alpha")}, []string{""}}, "0.0.0-alpha+"},
 	{&Version{0, 0, 0, []PRVersion{prstr("alpha")}, []string{"test", ""}}, "0.0.0-alpha+test."},
+	{&Version{1, 2, 3, nil, []string{""}}, "1.2.3+"},
+	{&Version{1, 2, 3, nil, []string{"a", "", "b"}}, "1.2.3+a..b"},
+	{&Version{1, 2, 3, nil, []string{"a.b"}}, "1.2.3+a.b."},
+	{&Version{1, 2, 3, nil, []string{"b_uild"}}, "1.2.3+b_uild"},
+	// leading zeroes in prerelease
+	{&Version{1, 2, 3, []PRVersion{prstr("01")}, nil}, "1.2.3-01"},
+	{&Version{1, 2, 3, []PRVersion{prstr("rc"), prstr("001")}, nil}, "1.2.3-rc.001"},
 }
 
 func TestWrongFormat(t *testing.T) {
@@ -256,6 +1063,45 @@ func TestCompareHelper(t *testing.T) {
 	}
 }
 
+func TestNewnessScore(t *testing.T) {
+	weights := [3]uint64{1000000, 1000, 1}
+	ordered := []string{"0.0.1", "0.1.0", "0.1.1", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		a, b := NewnessScore(MustParse(ordered[i-1]), weights), NewnessScore(MustParse(ordered[i]), weights)
+		if a >= b {
+			t.Errorf("Expected score of %q (%d) to be less than %q (%d)", ordered[i-1], a, ordered[i], b)
+		}
+	}
+	ordered = []string{"1.2.1", "1.2.2", "1.2.3-rc", "1.2.3", "1.2.4-0", "1.2.4"}
+	for _, w := range [][3]uint64{{100, 10, 2}, {1000000, 1000, 10}} {
+		for i := 1; i < len(ordered); i++ {
+			a, b := NewnessScore(MustParse(ordered[i-1]), w), NewnessScore(MustParse(ordered[i]), w)
+			if a >= b {
+				t.Errorf("Expected score of %q (%d) to be less than %q (%d) with weights %v", ordered[i-1], a, ordered[i], b, w)
+			}
+		}
+	}
+	if a, b := NewnessScore(MustParse("1.2.2"), [3]uint64{100, 10, 1}), NewnessScore(MustParse("1.2.3-rc"), [3]uint64{100, 10, 1}); a != 122 || b != 122 {
+		t.Errorf("Expected 1.2.2 and 1.2.3-rc to tie at 122 with a patch weight of 1, got %d and %d", a, b)
+	}
+	if s := NewnessScore(MustParse("1.2.3"), weights); s != 1002003 {
+		t.Errorf("Expected score %d, got %d", 1002003, s)
+	}
+	if s := NewnessScore(MustParse("0.0.0-rc.1"), weights); s != 0 {
+		t.Errorf("Expected score 0, got %d", s)
+	}
+
+	max := uint64(18446744073709551615)
+	for _, v := range []Version{{Major: max}, {Major: 1 << 62}, {Major: max / 1000000, Minor: max}} {
+		if s := NewnessScore(v, weights); s != max {
+			t.Errorf("Expected score of %q to saturate, got %d", v, s)
+		}
+	}
+	if s := NewnessScore(Version{Major: max / 2, Minor: max / 2, Patch: 2}, [3]uint64{1, 1, 1}); s != max {
+		t.Errorf("Expected sum to saturate, got %d", s)
+	}
+}
+
 const (
 	MAJOR = iota
 	MINOR
@@ -314,6 +1160,132 @@ func TestIncrements(t *testing.T) {
 	}
 }
 
+func TestIncrementsBy(t *testing.T) {
+	tests := []struct {
+		v        string
+		t        int
+		n        uint64
+		expected string
+	}{
+		{"1.2.3", PATCH, 3, "1.2.6"},
+		{"1.2.3", MINOR, 3, "1.5.0"},
+		{"1.2.3", MAJOR, 3, "4.0.0"},
+		{"1.2.3-rc.1+build", PATCH, 1, "1.2.4-rc.1+build"},
+		{"1.2.3", PATCH, 0, "1.2.3"},
+		{"1.2.3", MINOR, 0, "1.2.3"},
+		{"1.2.3", MAJOR, 0, "1.2.3"},
+		{"1.2.18446744073709551614", PATCH, 1, "1.2.18446744073709551615"},
+		{"1.2.18446744073709551614", PATCH, 2, ""},
+		{"1.2.3", PATCH, 18446744073709551615, ""},
+		{"1.18446744073709551615.3", MINOR, 1, ""},
+		{"18446744073709551615.2.3", MAJOR, 1, ""},
+		{"18446744073709551615.2.3", MAJOR, 0, "18446744073709551615.2.3"},
+		{"0.2.3", PATCH, 1, ""},
+		{"0.2.3", MINOR, 1, ""},
+		{"0.2.3", MAJOR, 1, ""},
+	}
+	for _, test := range tests {
+		v := MustParse(test.v)
+		var err error
+		switch test.t {
+		case PATCH:
+			err = v.IncrementPatchBy(test.n)
+		case MINOR:
+			err = v.IncrementMinorBy(test.n)
+		case MAJOR:
+			err = v.IncrementMajorBy(test.n)
+		}
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("Increment %q by %d, expecting error, got %q", test.v, test.n, v)
+			}
+			if v.String() != test.v {
+				t.Errorf("Increment %q by %d, expecting it to be unchanged, got %q", test.v, test.n, v)
+			}
+		} else if err != nil {
+			t.Errorf("Increment %q by %d, not expecting error, got %q", test.v, test.n, err)
+		} else if v.String() != test.expected {
+			t.Errorf("Increment %q by %d, expecting %q, got %q", test.v, test.n, test.expected, v)
+		}
+	}
+}
+
+func TestIncrementPrerelease(t *testing.T) {
+	tests := []struct {
+		v        string
+		expected string
+	}{
+		{"1.2.0-rc", "1.2.0-rc.1"},
+		{"1.2.0-rc.1", "1.2.0-rc.2"},
+		{"1.2.0-rc.9+build", "1.2.0-rc.10+build"},
+		{"1.2.0-alpha.beta", "1.2.0-alpha.beta.1"},
+		{"1.2.0-0", "1.2.0-1"},
+		{"1.2.0-1.alpha", "1.2.0-1.alpha.1"},
+		{"1.2.0", ""},
+		{"1.2.0-rc.18446744073709551615", ""},
+	}
+	for _, test := range tests {
+		v := MustParse(test.v)
+		original := v.Clone()
+		err := v.IncrementPrerelease()
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("Increment prerelease %q, expecting error, got %q", test.v, v)
+			}
+			if v.String() != original.String() {
+				t.Errorf("Increment prerelease, expecting %q to be unchanged, got %q", original, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Increment prerelease %q, not expecting error, got %q", test.v, err)
+		} else if v.String() != test.expected {
+			t.Errorf("Increment prerelease, expecting %q, got %q", test.expected, v)
+		} else if !v.GT(original) {
+			t.Errorf("Increment prerelease, expecting %q to be greater than %q", v, original)
+		}
+	}
+}
+
+func TestFinalizeOrBump(t *testing.T) {
+	tests := []struct {
+		v        string
+		expected string
+	}{
+		{"1.2.3-rc.1", "1.2.3"},
+		{"1.2.3-rc.1+build.5", "1.2.3"},
+		{"1.2.0-0", "1.2.0"},
+		{"1.2.3", "1.2.4"},
+		{"1.2.3+build.5", "1.2.4"},
+		{"0.2.3-beta", "0.2.3"},
+		{"0.2.3", "0.2.4"},
+		{"0.0.0+build", "0.0.1"},
+	}
+	for _, test := range tests {
+		v := MustParse(test.v)
+		err := v.FinalizeOrBump()
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("FinalizeOrBump %q, expecting error, got %q", test.v, v)
+			} else if v.String() != test.v {
+				t.Errorf("FinalizeOrBump, expecting %q to be unchanged, got %q", test.v, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("FinalizeOrBump %q, not expecting error, got %q", test.v, err)
+		} else if v.String() != test.expected {
+			t.Errorf("FinalizeOrBump, expecting %q, got %q", test.expected, v)
+		}
+	}
+
+	// IncrementPatch always bumps the number
+	v := MustParse("1.2.3-rc.1")
+	if err := v.IncrementPatch(); err != nil || v.String() != "1.2.4-rc.1" {
+		t.Errorf("Increment patch, expecting %q, got %q, %v", "1.2.4-rc.1", v, err)
+	}
+}
+
 func TestPreReleaseVersions(t *testing.T) {
 	p1, err := NewPRVersion("123")
 	if !p1.IsNumeric() {
@@ -337,6 +1309,43 @@ func TestPreReleaseVersions(t *testing.T) {
 	}
 }
 
+func TestInvalidPreReleaseVersions(t *testing.T) {
+	for _, s := range []string{"", "01", "beta!", "rc.1"} {
+		if _, err := NewPRVersion(s); err == nil {
+			t.Errorf("Expected error for prversion %q, got none", s)
+		}
+	}
+}
+
+func TestSetPrerelease(t *testing.T) {
+	v := MustParse("1.2.3+build")
+	if err := v.SetPrerelease("rc", "1"); err != nil {
+		t.Fatalf("Unexpected error %q", err)
+	}
+	if s := v.String(); s != "1.2.3-rc.1+build" {
+		t.Errorf("Expected %q, got %q", "1.2.3-rc.1+build", s)
+	}
+	if _, err := Parse(v.String()); err != nil {
+		t.Errorf("Expected %q to re-parse, got error %q", v, err)
+	}
+
+	for _, ids := range [][]string{{"01"}, {"beta!"}, {"rc", ""}} {
+		if err := v.SetPrerelease(ids...); err == nil {
+			t.Errorf("Expected error for prerelease %q, got none", ids)
+		}
+		if s := v.String(); s != "1.2.3-rc.1+build" {
+			t.Errorf("Expected failed SetPrerelease to leave %q unchanged, got %q", "1.2.3-rc.1+build", s)
+		}
+	}
+
+	if err := v.SetPrerelease(); err != nil {
+		t.Fatalf("Unexpected error %q", err)
+	}
+	if s := v.String(); s != "1.2.3+build" {
+		t.Errorf("Expected %q, got %q", "1.2.3+build", s)
+	}
+}
+
 func TestBuildMetaDataVersions(t *testing.T) {
 	_, err := NewBuildVersion("123")
 	if err != nil {
@@ -402,6 +1411,42 @@ func BenchmarkParseComplex(b *testing.B) {
 	}
 }
 
+func BenchmarkParseBytesString(b *testing.B) {
+	line := []byte("1.2.3")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		Parse(string(line))
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	line := []byte("1.2.3")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ParseBytes(line)
+	}
+}
+
+func BenchmarkParseBytesComplexString(b *testing.B) {
+	line := []byte("0.0.1-alpha.preview+123.456")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		Parse(string(line))
+	}
+}
+
+func BenchmarkParseBytesComplex(b *testing.B) {
+	line := []byte("0.0.1-alpha.preview+123.456")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ParseBytes(line)
+	}
+}
+
 func BenchmarkParseAverage(b *testing.B) {
 	l := len(formatTests)
 	b.ReportAllocs()
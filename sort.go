@@ -26,3 +26,130 @@ func (s Versions) Less(i, j int) bool {
 func Sort(versions []Version) {
 	sort.Sort(Versions(versions))
 }
+
+// SortWithBuild sorts a slice of versions like Sort, but orders versions of
+// equal precedence by their build meta data using CompareBuild, so the
+// result does not depend on the order of the input.
+func SortWithBuild(versions []Version) {
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].CompareBuild(versions[j]) < 0
+	})
+}
+
+// RankInSlice returns the index of v in the ascending sorted versions using
+// binary search. Versions are found by precedence, so build meta data is
+// ignored. It returns false if v is not in sorted.
+func RankInSlice(v Version, sorted []Version) (int, bool) {
+	i := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].GTE(v)
+	})
+	if i < len(sorted) && sorted[i].EQ(v) {
+		return i, true
+	}
+	return -1, false
+}
+
+// StableDedup returns the versions of vs with later duplicates removed,
+// keeping the first occurrence of each version. Versions are duplicates if
+// they have equal precedence, so build meta data is ignored. The order of
+// the input is preserved.
+func StableDedup(vs []Version) []Version {
+	seen := make(map[string]bool, len(vs))
+	out := make([]Version, 0, len(vs))
+	for _, v := range vs {
+		key := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Pre: v.Pre}.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// UpgradePath returns the available versions greater than from and less than
+// or equal to to, sorted in ascending order. The available slice is not
+// modified.
+func UpgradePath(from, to Version, available []Version) []Version {
+	var path []Version
+	for _, v := range available {
+		if v.GT(from) && v.LTE(to) {
+			path = append(path, v)
+		}
+	}
+	Sort(path)
+	return path
+}
+
+// CompatibleUpgrade returns the highest of the available versions in the
+// caret range of v, like "^v". For major version 0, the left-most non-zero
+// component must not change, so the upgrade of 0.2.3 stays below 0.3.0.
+// Prereleases are only considered if v is a prerelease itself. It returns
+// false if no available version is compatible.
+func (v Version) CompatibleUpgrade(available []Version) (Version, bool) {
+	compatible := RangeFor(v, MajorCompatible)
+	var best Version
+	found := false
+	for _, o := range available {
+		if len(o.Pre) > 0 && len(v.Pre) == 0 {
+			continue
+		}
+		// the core keeps out prereleases of the next incompatible version
+		if compatible(o) && compatible(o.Core()) && (!found || o.GT(best)) {
+			best, found = o, true
+		}
+	}
+	return best, found
+}
+
+// LatestByChannel returns the highest stable version and the highest
+// prerelease of vs in a single pass. The flags report whether vs contains a
+// stable version or a prerelease at all.
+func LatestByChannel(vs []Version) (stable Version, prerelease Version, hasStable, hasPre bool) {
+	for _, v := range vs {
+		if len(v.Pre) == 0 {
+			if !hasStable || v.GT(stable) {
+				stable, hasStable = v, true
+			}
+		} else if !hasPre || v.GT(prerelease) {
+			prerelease, hasPre = v, true
+		}
+	}
+	return
+}
+
+// HighestStable returns the greatest version of vs without prerelease. It
+// returns false if vs contains no stable version.
+func HighestStable(vs []Version) (Version, bool) {
+	stable, _, hasStable, _ := LatestByChannel(vs)
+	return stable, hasStable
+}
+
+// LatestResolution returns the version npm would tag as latest among the
+// available versions: the highest stable version, or if there is none and
+// includePre is set, the highest prerelease. It returns false if no version
+// qualifies.
+func LatestResolution(available []Version, includePre bool) (Version, bool) {
+	stable, pre, hasStable, hasPre := LatestByChannel(available)
+	if hasStable {
+		return stable, true
+	}
+	if includePre && hasPre {
+		return pre, true
+	}
+	return Version{}, false
+}
+
+// NextMatching returns the smallest of the candidates that is greater than
+// current and satisfies r. It returns false if no candidate qualifies, e.g.
+// because current is already the highest match.
+func NextMatching(current Version, candidates []Version, r Range) (Version, bool) {
+	var next Version
+	found := false
+	for _, v := range candidates {
+		if v.GT(current) && r(v) && (!found || v.LT(next)) {
+			next, found = v, true
+		}
+	}
+	return next, found
+}
@@ -18,6 +18,256 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestSortWithBuild(t *testing.T) {
+	correct := []string{
+		"1.2.3-rc.1+a",
+		"1.2.3",
+		"1.2.3+2",
+		"1.2.3+02",
+		"1.2.3+10",
+		"1.2.3+a",
+		"1.2.3+b",
+		"1.2.3+b.1",
+		"1.2.4",
+	}
+	for _, order := range [][]int{{8, 7, 6, 5, 4, 3, 2, 1, 0}, {4, 0, 7, 2, 8, 1, 5, 3, 6}} {
+		versions := make([]Version, len(correct))
+		for i, j := range order {
+			versions[i] = MustParse(correct[j])
+		}
+		SortWithBuild(versions)
+		for i, v := range versions {
+			if v.String() != correct[i] {
+				t.Fatalf("SortWithBuild returned wrong order: %s", versions)
+			}
+		}
+	}
+}
+
+func TestRankInSlice(t *testing.T) {
+	sorted := []Version{
+		MustParse("0.9.0"),
+		MustParse("1.0.0-rc.1"),
+		MustParse("1.0.0"),
+		MustParse("1.2.0"),
+		MustParse("2.0.0"),
+	}
+	tests := []struct {
+		v  string
+		i  int
+		ok bool
+	}{
+		{"0.9.0", 0, true},
+		{"1.0.0-rc.1", 1, true},
+		{"1.0.0", 2, true},
+		{"1.0.0+build", 2, true},
+		{"2.0.0", 4, true},
+		{"0.1.0", -1, false},
+		{"1.1.0", -1, false},
+		{"3.0.0", -1, false},
+	}
+	for _, tc := range tests {
+		if i, ok := RankInSlice(MustParse(tc.v), sorted); i != tc.i || ok != tc.ok {
+			t.Errorf("Invalid for case %q: Expected %d, %t, got: %d, %t", tc.v, tc.i, tc.ok, i, ok)
+		}
+	}
+	if i, ok := RankInSlice(MustParse("1.0.0"), nil); ok {
+		t.Errorf("Expected no rank in empty slice, got: %d", i)
+	}
+}
+
+func TestStableDedup(t *testing.T) {
+	versions := []Version{
+		MustParse("1.2.3+b"),
+		MustParse("1.0.0"),
+		MustParse("1.2.3+a"),
+		MustParse("1.2.3"),
+		MustParse("1.2.3-rc.1+a"),
+		MustParse("1.0.0+c"),
+		MustParse("1.2.3-rc.1+b"),
+	}
+	dedup := StableDedup(versions)
+
+	correct := []Version{versions[0], versions[1], versions[4]}
+	if !reflect.DeepEqual(dedup, correct) {
+		t.Fatalf("StableDedup returned wrong versions: %s", dedup)
+	}
+	if len(StableDedup(nil)) != 0 {
+		t.Fatalf("StableDedup of nil returned versions")
+	}
+}
+
+func TestUpgradePath(t *testing.T) {
+	available := []Version{
+		MustParse("2.0.0"),
+		MustParse("1.0.0"),
+		MustParse("1.1.0"),
+		MustParse("1.4.2"),
+		MustParse("2.1.0-rc.1"),
+		MustParse("3.0.0"),
+		MustParse("1.2.0"),
+	}
+	original := append([]Version(nil), available...)
+
+	path := UpgradePath(MustParse("1.1.0"), MustParse("2.1.0"), available)
+	correct := []Version{MustParse("1.2.0"), MustParse("1.4.2"), MustParse("2.0.0"), MustParse("2.1.0-rc.1")}
+	if !reflect.DeepEqual(path, correct) {
+		t.Fatalf("UpgradePath returned wrong path: %s", path)
+	}
+	if !reflect.DeepEqual(available, original) {
+		t.Fatalf("UpgradePath modified available versions: %s", available)
+	}
+
+	path = UpgradePath(MustParse("1.0.0"), MustParse("2.0.0"), available)
+	correct = []Version{MustParse("1.1.0"), MustParse("1.2.0"), MustParse("1.4.2"), MustParse("2.0.0")}
+	if !reflect.DeepEqual(path, correct) {
+		t.Fatalf("UpgradePath returned wrong path: %s", path)
+	}
+
+	if path := UpgradePath(MustParse("3.0.0"), MustParse("4.0.0"), available); len(path) != 0 {
+		t.Fatalf("UpgradePath returned path beyond available versions: %s", path)
+	}
+}
+
+func TestCompatibleUpgrade(t *testing.T) {
+	available := []Version{
+		MustParse("0.2.3"),
+		MustParse("0.2.9"),
+		MustParse("0.3.0"),
+		MustParse("0.0.3"),
+		MustParse("0.0.4"),
+		MustParse("1.2.3"),
+		MustParse("1.9.0"),
+		MustParse("1.10.0-rc.1"),
+		MustParse("2.0.0-beta"),
+		MustParse("2.0.0"),
+	}
+	tests := []struct {
+		v  string
+		o  string
+		ok bool
+	}{
+		{"1.2.3", "1.9.0", true},
+		{"1.0.0", "1.9.0", true},
+		{"1.9.0", "1.9.0", true},
+		{"1.10.0-alpha", "1.10.0-rc.1", true},
+		{"2.0.0", "2.0.0", true},
+		{"0.2.3", "0.2.9", true},
+		{"0.2.0", "0.2.9", true},
+		{"0.0.3", "0.0.3", true},
+		{"1.9.1", "", false},
+		{"3.0.0", "", false},
+	}
+	for _, tc := range tests {
+		o, ok := MustParse(tc.v).CompatibleUpgrade(available)
+		if ok != tc.ok || (ok && o.String() != tc.o) {
+			t.Errorf("Invalid for case %q: Expected %q, %t, got: %q, %t", tc.v, tc.o, tc.ok, o, ok)
+		}
+	}
+}
+
+func TestLatestByChannel(t *testing.T) {
+	vs := []Version{
+		MustParse("1.2.0"),
+		MustParse("2.0.0-rc.1"),
+		MustParse("1.10.0"),
+		MustParse("2.0.0-beta.3"),
+		MustParse("1.9.0"),
+		MustParse("1.11.0-alpha"),
+	}
+	stable, pre, hasStable, hasPre := LatestByChannel(vs)
+	if !hasStable || stable.String() != "1.10.0" {
+		t.Errorf("Expected latest stable %q, got %q, %t", "1.10.0", stable, hasStable)
+	}
+	if !hasPre || pre.String() != "2.0.0-rc.1" {
+		t.Errorf("Expected latest prerelease %q, got %q, %t", "2.0.0-rc.1", pre, hasPre)
+	}
+
+	_, pre, hasStable, hasPre = LatestByChannel(vs[1:2])
+	if hasStable || !hasPre || pre.String() != "2.0.0-rc.1" {
+		t.Errorf("Expected only prerelease %q, got %q, %t, %t", "2.0.0-rc.1", pre, hasStable, hasPre)
+	}
+
+	if _, _, hasStable, hasPre = LatestByChannel(nil); hasStable || hasPre {
+		t.Errorf("Expected no versions, got %t, %t", hasStable, hasPre)
+	}
+}
+
+func TestHighestStable(t *testing.T) {
+	vs := []Version{
+		MustParse("1.9.0"),
+		MustParse("2.0.0-rc.1"),
+		MustParse("1.10.0+build"),
+		MustParse("1.2.0"),
+	}
+	if v, ok := HighestStable(vs); !ok || v.String() != "1.10.0+build" {
+		t.Errorf("Expected highest stable %q, got %q, %t", "1.10.0+build", v, ok)
+	}
+	if v, ok := HighestStable(vs[1:2]); ok {
+		t.Errorf("Expected no stable version, got %q", v)
+	}
+	if v, ok := HighestStable(nil); ok {
+		t.Errorf("Expected no stable version, got %q", v)
+	}
+}
+
+func TestLatestResolution(t *testing.T) {
+	mixed := []Version{MustParse("1.2.0"), MustParse("2.0.0-rc.1"), MustParse("1.3.0")}
+	preOnly := []Version{MustParse("2.0.0-beta"), MustParse("2.0.0-rc.1")}
+	tests := []struct {
+		available  []Version
+		includePre bool
+		o          string
+		ok         bool
+	}{
+		{mixed, false, "1.3.0", true},
+		{mixed, true, "1.3.0", true},
+		{preOnly, false, "", false},
+		{preOnly, true, "2.0.0-rc.1", true},
+		{nil, true, "", false},
+	}
+	for _, tc := range tests {
+		v, ok := LatestResolution(tc.available, tc.includePre)
+		if ok != tc.ok || (ok && v.String() != tc.o) {
+			t.Errorf("Invalid for case %s, %t: Expected %q, %t, got: %q, %t", tc.available, tc.includePre, tc.o, tc.ok, v, ok)
+		}
+	}
+}
+
+func TestNextMatching(t *testing.T) {
+	candidates := []Version{
+		MustParse("1.4.0"),
+		MustParse("1.2.0"),
+		MustParse("2.0.0"),
+		MustParse("1.3.0"),
+		MustParse("1.2.5"),
+		MustParse("0.9.0"),
+	}
+	tests := []struct {
+		current string
+		r       string
+		o       string
+		ok      bool
+	}{
+		{"1.2.0", ">=1.0.0 <2.0.0", "1.2.5", true},
+		{"1.2.0", ">=1.3.0 <2.0.0", "1.3.0", true},
+		{"1.2.5", "<1.3.0", "", false},
+		{"1.4.0", ">=1.0.0 <2.0.0", "", false},
+		{"1.4.0", ">=1.0.0", "2.0.0", true},
+		{"0.1.0", ">=1.0.0", "1.2.0", true},
+		{"2.0.0", ">=0.0.0", "", false},
+	}
+	for _, tc := range tests {
+		v, ok := NextMatching(MustParse(tc.current), candidates, MustParseRange(tc.r))
+		if ok != tc.ok || (ok && v.String() != tc.o) {
+			t.Errorf("Invalid for case %q, %q: Expected %q, %t, got: %q, %t", tc.current, tc.r, tc.o, tc.ok, v, ok)
+		}
+	}
+	if v, ok := NextMatching(MustParse("1.0.0"), nil, MustParseRange(">=0.0.0")); ok {
+		t.Errorf("Expected no version for empty candidates, got %q", v)
+	}
+}
+
 func BenchmarkSort(b *testing.B) {
 	v100, _ := Parse("1.0.0")
 	v010, _ := Parse("0.1.0")
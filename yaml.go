@@ -0,0 +1,22 @@
+package semver
+
+// MarshalYAML implements the yaml.Marshaler interface of gopkg.in/yaml.v2
+// and gopkg.in/yaml.v3.
+func (v Version) MarshalYAML() (interface{}, error) {
+	return v.String(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of gopkg.in/yaml.v2,
+// which gopkg.in/yaml.v3 supports as well. Using the function signature keeps
+// this package free of a yaml dependency.
+func (v *Version) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var versionString string
+
+	if err = unmarshal(&versionString); err != nil {
+		return
+	}
+
+	*v, err = Parse(versionString)
+
+	return
+}
@@ -0,0 +1,73 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+// yamlUnmarshal returns an unmarshal function as passed by the yaml package
+// to UnmarshalYAML, decoding the given scalar value.
+func yamlUnmarshal(value interface{}) func(interface{}) error {
+	return func(out interface{}) error {
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("cannot unmarshal into string")
+		}
+		*(out.(*string)) = s
+		return nil
+	}
+}
+
+func TestYAMLMarshal(t *testing.T) {
+	versionString := "3.1.4-alpha.1.5.9+build.2.6.5"
+	v, err := Parse(versionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versionYAML, err := v.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if versionYAML != versionString {
+		t.Fatalf("YAML marshaled semantic version not equal: expected %q, got %q", versionString, versionYAML)
+	}
+}
+
+func TestYAMLUnmarshal(t *testing.T) {
+	versionString := "3.1.4-alpha.1.5.9+build.2.6.5"
+
+	var v Version
+	if err := v.UnmarshalYAML(yamlUnmarshal(versionString)); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.String() != versionString {
+		t.Fatalf("YAML unmarshaled semantic version not equal: expected %q, got %q", versionString, v.String())
+	}
+
+	if err := v.UnmarshalYAML(yamlUnmarshal("3.1.4.1.5.9.2.6.5-other-digits-of-pi")); err == nil {
+		t.Fatal("expected YAML unmarshal error, got nil")
+	}
+
+	if err := v.UnmarshalYAML(yamlUnmarshal(3.1)); err == nil {
+		t.Fatal("expected YAML unmarshal error, got nil")
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	for _, test := range formatTests {
+		out, err := test.v.MarshalYAML()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v Version
+		if err := v.UnmarshalYAML(yamlUnmarshal(out)); err != nil {
+			t.Fatalf("YAML unmarshal of %q failed: %s", out, err)
+		}
+		if v.String() != test.v.String() {
+			t.Errorf("YAML round trip not equal: expected %q, got %q", test.v, v)
+		}
+	}
+}